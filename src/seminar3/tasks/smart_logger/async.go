@@ -0,0 +1,265 @@
+package smartlogger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy что делать с записью, когда очередь AsyncWriter заполнена
+type OverflowPolicy int
+
+const (
+	Block      OverflowPolicy = iota // ждать освобождения места
+	DropNewest                       // отбросить новую запись
+	DropOldest                       // вытеснить самую старую запись из очереди
+)
+
+// Значения по умолчанию для AsyncConfig
+const (
+	defaultQueueSize    = 1024
+	defaultCloseTimeout = 5 * time.Second
+)
+
+// ErrFlushTimeout возвращается, если очередь не успела опустеть до дедлайна
+var ErrFlushTimeout = errors.New("очередь логов не опустела до истечения таймаута")
+
+// AsyncConfig параметры асинхронной записи
+type AsyncConfig struct {
+	QueueSize    int            // размер очереди, по умолчанию 1024
+	Policy       OverflowPolicy // поведение при переполнении, по умолчанию Block
+	CloseTimeout time.Duration  // сколько Close ждет опустошения очереди, по умолчанию 5s
+	OnError      func(error)    // обработчик ошибок фоновой записи
+}
+
+// AsyncWriter пишет в w из фоновой горутины через ограниченную очередь,
+// поэтому Write не ждет медленный вывод (кроме политики Block при полной очереди).
+type AsyncWriter struct {
+	w       io.Writer
+	config  AsyncConfig
+	queue   chan []byte
+	done    chan struct{}
+	dropped atomic.Int64
+
+	closeMu sync.RWMutex // защищает closed; не удерживается во время отправки в очередь
+	closed  bool
+	closing chan struct{}  // закрывается в Close и прерывает ожидание места в очереди
+	writers sync.WaitGroup // Write, которые еще могут отправить в queue
+
+	abandoned atomic.Bool // вывод закрыт по таймауту, остаток очереди отбрасывается
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending int // записи в очереди и в процессе записи
+}
+
+func NewAsyncWriter(w io.Writer, config AsyncConfig) *AsyncWriter {
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultQueueSize
+	}
+	if config.CloseTimeout <= 0 {
+		config.CloseTimeout = defaultCloseTimeout
+	}
+
+	a := &AsyncWriter{
+		w:       w,
+		config:  config,
+		queue:   make(chan []byte, config.QueueSize),
+		done:    make(chan struct{}),
+		closing: make(chan struct{}),
+	}
+	a.cond = sync.NewCond(&a.mu)
+
+	go a.run()
+	return a
+}
+
+// Write ставит копию p в очередь. Отброшенная по политике запись
+// не считается ошибкой и учитывается в Dropped. Если Close начался,
+// пока Write ждал места в очереди (Block), запись отбрасывается
+// и возвращается os.ErrClosed.
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	a.closeMu.RLock()
+	if a.closed {
+		a.closeMu.RUnlock()
+		return 0, os.ErrClosed
+	}
+	a.writers.Add(1)
+	a.closeMu.RUnlock()
+	defer a.writers.Done()
+
+	data := make([]byte, len(p))
+	copy(data, p)
+	a.addPending(1)
+
+	switch a.config.Policy {
+	case DropNewest:
+		select {
+		case a.queue <- data:
+		default:
+			a.drop()
+		}
+	case DropOldest:
+		for {
+			select {
+			case a.queue <- data:
+				return len(p), nil
+			default:
+			}
+			select {
+			case <-a.queue:
+				a.drop()
+			default:
+			}
+		}
+	default:
+		select {
+		case a.queue <- data:
+		case <-a.closing:
+			a.drop()
+			return 0, os.ErrClosed
+		}
+	}
+	return len(p), nil
+}
+
+// Dropped возвращает число отброшенных из-за переполнения записей
+func (a *AsyncWriter) Dropped() int64 {
+	return a.dropped.Load()
+}
+
+// Flush ждет, пока очередь опустеет и последняя запись завершится,
+// но не дольше timeout. При истечении времени возвращает ErrFlushTimeout.
+func (a *AsyncWriter) Flush(timeout time.Duration) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	timedOut := false
+	timer := time.AfterFunc(timeout, func() {
+		a.mu.Lock()
+		timedOut = true
+		a.cond.Broadcast()
+		a.mu.Unlock()
+	})
+	defer timer.Stop()
+
+	for a.pending > 0 && !timedOut {
+		a.cond.Wait()
+	}
+	if a.pending > 0 {
+		return fmt.Errorf("%w: осталось %d записей", ErrFlushTimeout, a.pending)
+	}
+	return nil
+}
+
+// Sync сбрасывает очередь и буферы нижележащего вывода
+func (a *AsyncWriter) Sync() error {
+	if err := a.Flush(a.config.CloseTimeout); err != nil {
+		return err
+	}
+	if syncer, ok := a.w.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
+
+// Close перестает принимать записи, ждет опустошения очереди не дольше
+// CloseTimeout и закрывает нижележащий вывод. Если очередь не успела
+// опустеть, вывод все равно закрывается, а оставшиеся записи отбрасываются.
+func (a *AsyncWriter) Close() error {
+	a.closeMu.Lock()
+	if a.closed {
+		a.closeMu.Unlock()
+		return nil
+	}
+	a.closed = true
+	a.closeMu.Unlock()
+
+	// новые Write уже не начнутся, а ждущие места в очереди прерываются,
+	// поэтому queue можно закрыть, не дожидаясь зависшего вывода
+	close(a.closing)
+	a.writers.Wait()
+	close(a.queue)
+
+	if err := a.Flush(a.config.CloseTimeout); err != nil {
+		// фоновая горутина может висеть на записи: не ждем ее,
+		// а закрываем вывод, чтобы не утек файл или сокет
+		a.abandoned.Store(true)
+		return errors.Join(err, closeOutput(a.w))
+	}
+	<-a.done
+	return closeOutput(a.w)
+}
+
+func (a *AsyncWriter) run() {
+	defer close(a.done)
+	for data := range a.queue {
+		if a.abandoned.Load() {
+			a.drop()
+			continue
+		}
+		if _, err := a.w.Write(data); err != nil && a.config.OnError != nil {
+			a.config.OnError(err)
+		}
+		a.addPending(-1)
+	}
+}
+
+func (a *AsyncWriter) drop() {
+	a.dropped.Add(1)
+	a.addPending(-1)
+}
+
+func (a *AsyncWriter) addPending(delta int) {
+	a.mu.Lock()
+	a.pending += delta
+	if a.pending == 0 {
+		a.cond.Broadcast()
+	}
+	a.mu.Unlock()
+}
+
+// WithAsync включает асинхронную запись: output оборачивается в AsyncWriter
+func WithAsync(config AsyncConfig) Option {
+	return func(sl *SmartLogger) {
+		sl.async = &config
+	}
+}
+
+// Flush ждет, пока асинхронные очереди output и приемников опустеют,
+// но не дольше timeout каждая. Для синхронного вывода ничего не делает.
+func (sl *SmartLogger) Flush(timeout time.Duration) error {
+	sl.mu.Lock()
+	defer sl.unlock()
+	sl.flushPartial()
+	sl.flushRepeats()
+	return sl.eachWriter(func(w interface{}) error {
+		if flusher, ok := w.(interface{ Flush(time.Duration) error }); ok {
+			return flusher.Flush(timeout)
+		}
+		return nil
+	})
+}
+
+// GetDroppedCount возвращает число записей, отброшенных асинхронной очередью
+func (sl *SmartLogger) GetDroppedCount() int64 {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	if async, ok := sl.output.(*AsyncWriter); ok {
+		return async.Dropped()
+	}
+	return 0
+}
+
+// closeOutput закрывает w, если это io.Closer. Стандартные потоки
+// os.Stdout и os.Stderr не закрываются.
+func closeOutput(w interface{}) error {
+	if closer, ok := w.(io.Closer); ok && w != os.Stdout && w != os.Stderr {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package smartlogger
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedWriter блокирует каждую запись, пока не открыт gate
+type gatedWriter struct {
+	mu      sync.Mutex
+	buf     strings.Builder
+	started chan struct{}
+	gate    chan struct{}
+	once    sync.Once
+	closed  bool
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{started: make(chan struct{}), gate: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.started) })
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gatedWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	return nil
+}
+
+func (w *gatedWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+// fillQueue пишет первую запись, ждет, пока фоновая горутина заберет ее
+// и заблокируется, затем пишет остальные
+func fillQueue(t *testing.T, a *AsyncWriter, w *gatedWriter, messages ...string) {
+	t.Helper()
+	_, err := a.Write([]byte(messages[0]))
+	require.NoError(t, err)
+	<-w.started
+	for _, msg := range messages[1:] {
+		_, err := a.Write([]byte(msg))
+		require.NoError(t, err)
+	}
+}
+
+func TestAsyncDropNewest(t *testing.T) {
+	w := newGatedWriter()
+	a := NewAsyncWriter(w, AsyncConfig{QueueSize: 2, Policy: DropNewest})
+
+	fillQueue(t, a, w, "1 ", "2 ", "3 ", "4 ", "5 ")
+	assert.Equal(t, int64(2), a.Dropped())
+
+	close(w.gate)
+	require.NoError(t, a.Flush(time.Second))
+	assert.Equal(t, "1 2 3 ", w.String())
+}
+
+func TestAsyncDropOldest(t *testing.T) {
+	w := newGatedWriter()
+	a := NewAsyncWriter(w, AsyncConfig{QueueSize: 2, Policy: DropOldest})
+
+	fillQueue(t, a, w, "1 ", "2 ", "3 ", "4 ", "5 ")
+	assert.Equal(t, int64(2), a.Dropped())
+
+	close(w.gate)
+	require.NoError(t, a.Flush(time.Second))
+	assert.Equal(t, "1 4 5 ", w.String())
+}
+
+func TestAsyncBlock(t *testing.T) {
+	w := newGatedWriter()
+	a := NewAsyncWriter(w, AsyncConfig{QueueSize: 1, Policy: Block})
+
+	fillQueue(t, a, w, "1 ", "2 ")
+
+	written := make(chan struct{})
+	go func() {
+		a.Write([]byte("3 "))
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("Write не должен завершиться при полной очереди")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(w.gate)
+	<-written
+	require.NoError(t, a.Flush(time.Second))
+	assert.Equal(t, "1 2 3 ", w.String())
+	assert.Zero(t, a.Dropped())
+}
+
+func TestAsyncFlushDeadline(t *testing.T) {
+	w := newGatedWriter()
+	a := NewAsyncWriter(w, AsyncConfig{QueueSize: 4})
+
+	fillQueue(t, a, w, "1 ", "2 ")
+	assert.ErrorIs(t, a.Flush(10*time.Millisecond), ErrFlushTimeout)
+
+	close(w.gate)
+	assert.NoError(t, a.Flush(time.Second))
+}
+
+func TestAsyncClose(t *testing.T) {
+	w := newGatedWriter()
+	a := NewAsyncWriter(w, AsyncConfig{QueueSize: 4, CloseTimeout: time.Second})
+
+	fillQueue(t, a, w, "1 ", "2 ")
+	close(w.gate)
+	require.NoError(t, a.Close())
+
+	assert.Equal(t, "1 2 ", w.String())
+	assert.True(t, w.closed)
+
+	_, err := a.Write([]byte("late"))
+	assert.Error(t, err)
+	assert.NoError(t, a.Close(), "повторный Close безопасен")
+}
+
+func TestAsyncCloseDeadline(t *testing.T) {
+	w := newGatedWriter()
+	a := NewAsyncWriter(w, AsyncConfig{QueueSize: 4, CloseTimeout: 10 * time.Millisecond})
+
+	fillQueue(t, a, w, "1 ", "2 ", "3 ")
+	assert.ErrorIs(t, a.Close(), ErrFlushTimeout)
+	assert.True(t, w.closed, "вывод закрывается и после таймаута")
+
+	close(w.gate)
+	require.NoError(t, a.Flush(time.Second))
+	assert.Equal(t, "1 ", w.String(), "остаток очереди не пишется в закрытый вывод")
+	assert.Equal(t, int64(2), a.Dropped())
+}
+
+func TestAsyncCloseWithBlockedWriter(t *testing.T) {
+	w := newGatedWriter()
+	defer close(w.gate)
+	a := NewAsyncWriter(w, AsyncConfig{QueueSize: 1, Policy: Block, CloseTimeout: 20 * time.Millisecond})
+	fillQueue(t, a, w, "1 ", "2 ")
+
+	writeErr := make(chan error, 1)
+	go func() {
+		_, err := a.Write([]byte("3 "))
+		writeErr <- err
+	}()
+	time.Sleep(10 * time.Millisecond) // Write ждет места в полной очереди
+
+	closed := make(chan error, 1)
+	go func() { closed <- a.Close() }()
+	select {
+	case err := <-closed:
+		assert.ErrorIs(t, err, ErrFlushTimeout)
+	case <-time.After(time.Second):
+		t.Fatal("Close не уложился в CloseTimeout при зависшем выводе")
+	}
+	assert.ErrorIs(t, <-writeErr, os.ErrClosed)
+	assert.True(t, w.closed)
+}
+
+// gatedCloseFailer gatedWriter, который не может закрыться
+type gatedCloseFailer struct {
+	*gatedWriter
+}
+
+func (gatedCloseFailer) Close() error {
+	return errCloseFailed
+}
+
+func TestAsyncCloseDeadlineJoinsCloseError(t *testing.T) {
+	w := newGatedWriter()
+	a := NewAsyncWriter(gatedCloseFailer{w}, AsyncConfig{QueueSize: 4, CloseTimeout: 10 * time.Millisecond})
+
+	fillQueue(t, a, w, "1 ")
+	err := a.Close()
+	assert.ErrorIs(t, err, ErrFlushTimeout)
+	assert.ErrorIs(t, err, errCloseFailed)
+	close(w.gate)
+}
+
+func TestLoggerWithAsync(t *testing.T) {
+	w := newGatedWriter()
+	close(w.gate)
+	logger := NewSmartLogger(w, "ASYNC", WithClock(testClock), WithAsync(AsyncConfig{QueueSize: 16}))
+
+	for i := 0; i < 10; i++ {
+		logger.Info("message %d", i)
+	}
+	require.NoError(t, logger.Flush(time.Second))
+	assert.Equal(t, 10, strings.Count(w.String(), "ASYNC [INFO]: message"))
+	assert.Zero(t, logger.GetDroppedCount())
+
+	logger.Info("last")
+	require.NoError(t, logger.Close())
+	assert.True(t, strings.HasSuffix(w.String(), "ASYNC [INFO]: last\n"))
+	assert.True(t, w.closed)
+}
+
+func TestLoggerAsyncDroppedCount(t *testing.T) {
+	w := newGatedWriter()
+	logger := NewSmartLogger(w, "ASYNC", WithAsync(AsyncConfig{QueueSize: 1, Policy: DropNewest}))
+
+	logger.Info("first")
+	<-w.started
+	logger.Info("queued")
+	logger.Info("dropped")
+
+	assert.Equal(t, int64(1), logger.GetDroppedCount())
+	assert.Equal(t, 3, logger.GetLogCount())
+	close(w.gate)
+	require.NoError(t, logger.Flush(time.Second))
+}
@@ -0,0 +1,121 @@
+package smartlogger
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Максимальная глубина стека, попадающая в запись
+const maxStackDepth = 64
+
+// Число кадров между write и пользовательским кодом: log/logw и метод уровня (Info, Errorw...)
+const writeCallerDepth = 2
+
+// WithCaller включает запись места вызова (file:line) в каждую запись
+func WithCaller() Option {
+	return func(sl *SmartLogger) {
+		sl.caller = true
+	}
+}
+
+// WithStacktrace включает запись полного стека для записей уровня level и выше,
+// обычно WithStacktrace(Error)
+func WithStacktrace(level Level) Option {
+	return func(sl *SmartLogger) {
+		sl.stackLevel = level
+		sl.stack = true
+	}
+}
+
+// AddCallerSkip возвращает дочерний логгер, пропускающий еще skip кадров
+// при определении места вызова. Нужен для собственных функций-оберток:
+//
+//	var log = logger.AddCallerSkip(1)
+//	func logError(err error) { log.Error("%v", err) } // в записи будет строка вызова logError
+func (sl *SmartLogger) AddCallerSkip(skip int) *SmartLogger {
+	child := sl.clone()
+	child.callerSkip += skip
+	return child
+}
+
+// capture заполняет место вызова и стек записи. skip — число кадров
+// между вызывающей capture функцией и пользовательским кодом.
+// Настройки caller и stack не меняются после создания, поэтому мьютекс не нужен.
+func (sl *SmartLogger) capture(entry *Entry, skip int) {
+	needStack := sl.stack && entry.Level >= sl.stackLevel
+	if !sl.caller && !needStack {
+		return
+	}
+
+	// 0 — runtime.Callers, 1 — capture, 2 — вызывающая функция, затем skip кадров
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(3+skip+sl.callerSkip, pcs)
+	if n == 0 {
+		return
+	}
+	sl.fillCaller(entry, pcs[:n], needStack)
+}
+
+// fillCaller заполняет запись по уже собранному стеку, pcs[0] — место вызова
+func (sl *SmartLogger) fillCaller(entry *Entry, pcs []uintptr, needStack bool) {
+	entry.pc = pcs[0]
+	if sl.caller {
+		frame, _ := runtime.CallersFrames(pcs[:1]).Next()
+		entry.Caller = shortCaller(frame)
+	}
+	if needStack {
+		entry.Stack = formatStack(pcs)
+	}
+}
+
+// captureFromPC заполняет запись для вызова через slog, где место вызова
+// уже известно по pc. Стек обрезается до кадра с этим pc.
+func (sl *SmartLogger) captureFromPC(entry *Entry, pc uintptr) {
+	needStack := sl.stack && entry.Level >= sl.stackLevel
+	if pc == 0 || (!sl.caller && !needStack) {
+		return
+	}
+
+	pcs := []uintptr{pc}
+	if needStack {
+		all := make([]uintptr, maxStackDepth)
+		all = all[:runtime.Callers(2, all)]
+		for i, p := range all {
+			if p == pc {
+				pcs = all[i:]
+				break
+			}
+		}
+	}
+	sl.fillCaller(entry, pcs, needStack)
+}
+
+// shortCaller возвращает "dir/file.go:line" — последний каталог и имя файла
+func shortCaller(frame runtime.Frame) string {
+	dir, file := filepath.Split(frame.File)
+	return filepath.Base(dir) + "/" + file + ":" + strconv.Itoa(frame.Line)
+}
+
+// formatStack форматирует стек как в выводе panic:
+// имя функции и на следующей строке с табуляцией file:line
+func formatStack(pcs []uintptr) string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
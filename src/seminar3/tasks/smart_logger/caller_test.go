@@ -0,0 +1,149 @@
+package smartlogger
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nextLine возвращает "smart_logger/file_test.go:N" для строки, следующей за вызовом
+func nextLine() string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("smart_logger/%s:%d", filepath.Base(file), line+1)
+}
+
+// logFailure обертка, место вызова которой должно попасть в лог
+func logFailure(logger *SmartLogger, err error) {
+	logger.Error("операция не удалась: %v", err)
+}
+
+func TestCallerText(t *testing.T) {
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "APP", WithClock(testClock), WithCaller())
+
+	want := nextLine()
+	logger.Info("hello")
+
+	assert.Equal(t, "2024-03-15 10:30:45 APP [INFO] "+want+": hello\n", buf.String())
+}
+
+func TestCallerAllMethods(t *testing.T) {
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "", WithCaller(), WithExitFunc(func(int) {}))
+	logger.SetLevel(Trace)
+
+	_, _, base, _ := runtime.Caller(0)
+	calls := []func(){
+		func() { logger.Trace("t") },
+		func() { logger.Debug("d") },
+		func() { logger.Info("i") },
+		func() { logger.Warn("w") },
+		func() { logger.Error("e") },
+		func() { logger.Fatal("f") },
+		func() { logger.Tracew("t") },
+		func() { logger.Debugw("d") },
+		func() { logger.Infow("i") },
+		func() { logger.Warnw("w") },
+		func() { logger.Errorw("e") },
+		func() { logger.Fatalw("f") },
+		func() { logger.With("k", "v").Info("child") },
+	}
+	for _, call := range calls {
+		call()
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, len(calls))
+	for i, line := range lines {
+		// i-й вызов находится на строке base+2+i
+		assert.Contains(t, line, fmt.Sprintf("smart_logger/caller_test.go:%d:", base+2+i), "запись %d", i)
+	}
+}
+
+func TestCallerSkipThroughWrapper(t *testing.T) {
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "", WithCaller()).AddCallerSkip(1)
+
+	want := nextLine()
+	logFailure(logger, fmt.Errorf("timeout"))
+
+	assert.Contains(t, buf.String(), "[ERROR] "+want+": операция не удалась: timeout")
+
+	// With сохраняет пропуск кадров
+	buf.Reset()
+	want = nextLine()
+	logFailure(logger.With("city", "Moscow"), fmt.Errorf("timeout"))
+	assert.Contains(t, buf.String(), want)
+}
+
+func TestCallerJSON(t *testing.T) {
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "APP", WithEncoder(JSONEncoder{}), WithCaller())
+
+	want := nextLine()
+	logger.Infow("hello", "k", 1)
+
+	records := parseJSONLines(t, []byte(buf.String()))
+	require.Len(t, records, 1)
+	assert.Equal(t, want, records[0][CallerKey])
+	assert.NotContains(t, records[0], StackKey)
+}
+
+func TestStacktraceForErrors(t *testing.T) {
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "", WithEncoder(JSONEncoder{}), WithStacktrace(Error))
+
+	logger.Warn("без стека")
+	logger.Error("со стеком")
+
+	records := parseJSONLines(t, []byte(buf.String()))
+	require.Len(t, records, 2)
+	assert.NotContains(t, records[0], StackKey)
+	assert.NotContains(t, records[1], CallerKey)
+
+	stack, ok := records[1][StackKey].(string)
+	require.True(t, ok)
+	frames := strings.Split(stack, "\n")
+	assert.Equal(t, "example/src/seminar3/tasks/smart_logger.TestStacktraceForErrors", frames[0])
+	assert.Contains(t, frames[1], "smart_logger/caller_test.go:")
+	assert.NotContains(t, stack, "smart_logger.(*SmartLogger)")
+}
+
+func TestStacktraceText(t *testing.T) {
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "", WithCaller(), WithStacktrace(Error))
+
+	want := nextLine()
+	logger.Error("boom")
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Contains(t, lines[0], "[ERROR] "+want+": boom")
+	assert.Equal(t, "example/src/seminar3/tasks/smart_logger.TestStacktraceText", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "\t"), lines[2])
+	assert.True(t, strings.HasSuffix(lines[2], strings.TrimPrefix(want, "smart_logger")), lines[2])
+}
+
+func TestCallerThroughSlog(t *testing.T) {
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "", WithCaller(), WithStacktrace(Error))
+	log := slog.New(NewSlogHandler(logger))
+
+	want := nextLine()
+	log.Error("boom")
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Contains(t, lines[0], "[ERROR] "+want+": boom")
+	assert.Equal(t, "example/src/seminar3/tasks/smart_logger.TestCallerThroughSlog", lines[1])
+}
+
+func TestCallerDisabledByDefault(t *testing.T) {
+	logger, buf := newTestLogger("APP")
+	logger.Error("boom")
+	assert.Equal(t, "2024-03-15 10:30:45 APP [ERROR]: boom\n", buf.String())
+}
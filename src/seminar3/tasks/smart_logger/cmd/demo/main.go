@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	smartlogger "example/src/seminar3/tasks/smart_logger"
+)
+
+func main() {
+	fmt.Println("=== Демонстрация SmartLogger ===")
+
+	// 1. Создаем логгер для консоли: цвета только в терминале и без NO_COLOR
+	consoleLogger := smartlogger.NewSmartLogger(os.Stdout, "APP", smartlogger.WithColor(smartlogger.ColorAuto))
+	defer consoleLogger.Close()
+
+	// Используем как обычный логгер
+	consoleLogger.Info("Приложение запущено")
+	consoleLogger.Warn("Нагрузка выше обычной: %.1f%%", 85.5)
+	consoleLogger.Error("Ошибка подключения к БД")
+
+	// 2. Используем как io.Writer
+	fmt.Println("\n=== Использование как io.Writer ===")
+	fmt.Fprintf(consoleLogger, "Это сообщение через fmt.Fprintf\n")
+
+	// 3. Демонстрация интерфейсов Stringer и GoStringer
+	fmt.Println("\n=== Stringer и GoStringer ===")
+	fmt.Println("String():", consoleLogger.String())
+	fmt.Printf("GoString(): %#v\n", consoleLogger)
+
+	// 4. Логгер в буфер (удовлетворяет io.Writer)
+	fmt.Println("\n=== Логгер в буфер ===")
+	var buf strings.Builder
+	bufferLogger := smartlogger.NewSmartLogger(&buf, "TEST")
+	bufferLogger.Info("Тестовое сообщение 1")
+	bufferLogger.Warn("Тестовое сообщение 2")
+
+	fmt.Println("Логи в буфере:")
+	fmt.Print(buf.String())
+	fmt.Printf("Всего логов: %d\n", bufferLogger.GetLogCount())
+	fmt.Println("Статистика:", bufferLogger.Stats())
+
+	// 5. Фильтрация по уровню
+	fmt.Println("\n=== Фильтрация по уровню ===")
+	filteredLogger := smartlogger.NewSmartLogger(os.Stdout, "FILTERED")
+	filteredLogger.SetLevel(smartlogger.Warn) // Только Warn и Error
+
+	filteredLogger.Info("Это сообщение НЕ должно появиться") // Не появится
+	filteredLogger.Warn("А это должно появиться")            // Появится
+	filteredLogger.Error("И это тоже")                       // Появится
+
+	// 6. Использование в функциях, принимающие io.Writer
+	fmt.Println("\n=== Использование с стандартными функциями ===")
+	writeToLogger(consoleLogger, "Сообщение через функцию")
+
+	// 7. Структурированные поля и дочерние логгеры
+	fmt.Println("\n=== Структурированные поля ===")
+	requestLogger := consoleLogger.With("request_id", "42")
+	requestLogger.Infow("Запрос обработан", "city", "Moscow", "latency", 150*time.Millisecond)
+}
+
+// Функция, принимающая io.Writer - наш логгер подходит!
+func writeToLogger(w io.Writer, message string) {
+	fmt.Fprintf(w, "Пишем в io.Writer: %s\n", message)
+}
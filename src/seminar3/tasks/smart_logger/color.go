@@ -0,0 +1,127 @@
+package smartlogger
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+)
+
+// ColorMode когда использовать ANSI цвета
+type ColorMode int
+
+const (
+	ColorNever  ColorMode = iota // без цветов
+	ColorAlways                  // всегда, как EnableColor
+	ColorAuto                    // только если вывод — терминал, с учетом NO_COLOR и FORCE_COLOR
+)
+
+// ColorScope какую часть строки раскрашивать
+type ColorScope int
+
+const (
+	ColorLevel   ColorScope = iota // только уровень
+	ColorMessage                   // только сообщение
+	ColorLine                      // всю строку
+)
+
+// Palette ANSI коды SGR для уровней, например "31" или "1;31".
+// Отсутствующие уровни берутся из DefaultPalette.
+type Palette map[Level]string
+
+// DefaultPalette цвета уровней по умолчанию
+var DefaultPalette = Palette{
+	Trace: "37",
+	Debug: "36",
+	Info:  "32",
+	Warn:  "33",
+	Error: "31",
+	Fatal: "35",
+}
+
+// code возвращает код цвета уровня из палитры или из DefaultPalette
+func (p Palette) code(level Level) string {
+	if c, ok := p[level]; ok {
+		return c
+	}
+	if c, ok := DefaultPalette[level]; ok {
+		return c
+	}
+	return "37"
+}
+
+// writeColored пишет text, обернутый в ANSI код
+func writeColored(buf *bytes.Buffer, code, text string) {
+	buf.WriteString("\033[")
+	buf.WriteString(code)
+	buf.WriteByte('m')
+	buf.WriteString(text)
+	buf.WriteString("\033[0m")
+}
+
+// WithColor задает режим цветного вывода
+func WithColor(mode ColorMode) Option {
+	return func(sl *SmartLogger) {
+		sl.colorMode = mode
+	}
+}
+
+// SetColorMode меняет режим цветного вывода. В режиме ColorAuto
+// решение принимается сразу по текущему output и переменным окружения.
+func (sl *SmartLogger) SetColorMode(mode ColorMode) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.isColor = useColor(mode, unwrapOutput(sl.output))
+}
+
+// SetColorMode меняет режим цветного вывода приемника, см. SmartLogger.SetColorMode
+func (s *WriterSink) SetColorMode(mode ColorMode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.isColor = useColor(mode, unwrapOutput(s.w))
+}
+
+// unwrapOutput возвращает вывод, скрытый за AsyncWriter
+func unwrapOutput(w io.Writer) io.Writer {
+	if async, ok := w.(*AsyncWriter); ok {
+		return async.w
+	}
+	return w
+}
+
+// useColor решает, нужны ли цвета для w. В режиме ColorAuto FORCE_COLOR
+// включает цвета (FORCE_COLOR=0 или false — выключает), иначе непустой
+// NO_COLOR их выключает, иначе цвета используются только для терминала.
+func useColor(mode ColorMode, w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if force, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		switch strings.ToLower(force) {
+		case "0", "false":
+			return false
+		}
+		return true
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal сообщает, является ли w символьным устройством (терминалом)
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,133 @@
+package smartlogger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clearColorEnv убирает переменные окружения, влияющие на ColorAuto
+func clearColorEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"NO_COLOR", "FORCE_COLOR"} {
+		if value, ok := os.LookupEnv(name); ok {
+			require.NoError(t, os.Unsetenv(name))
+			t.Cleanup(func() { os.Setenv(name, value) })
+		}
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "app.log"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	tests := []struct {
+		name string
+		mode ColorMode
+		env  map[string]string
+		want bool
+	}{
+		{"never", ColorNever, nil, false},
+		{"always ignores NO_COLOR", ColorAlways, map[string]string{"NO_COLOR": "1"}, true},
+		{"auto file", ColorAuto, nil, false},
+		{"auto NO_COLOR", ColorAuto, map[string]string{"NO_COLOR": "1"}, false},
+		{"auto empty NO_COLOR", ColorAuto, map[string]string{"NO_COLOR": ""}, false},
+		{"auto FORCE_COLOR", ColorAuto, map[string]string{"FORCE_COLOR": "1"}, true},
+		{"auto FORCE_COLOR wins", ColorAuto, map[string]string{"FORCE_COLOR": "true", "NO_COLOR": "1"}, true},
+		{"auto FORCE_COLOR=0", ColorAuto, map[string]string{"FORCE_COLOR": "0"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearColorEnv(t)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			assert.Equal(t, tt.want, useColor(tt.mode, file))
+		})
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+	defer w.Close()
+
+	assert.False(t, isTerminal(w))
+	assert.False(t, isTerminal(&strings.Builder{}))
+}
+
+func TestLoggerColorAuto(t *testing.T) {
+	clearColorEnv(t)
+
+	var plain strings.Builder
+	NewSmartLogger(&plain, "APP", WithClock(testClock), WithColor(ColorAuto)).Error("e")
+	assert.Equal(t, "2024-03-15 10:30:45 APP [ERROR]: e\n", plain.String())
+
+	t.Setenv("FORCE_COLOR", "1")
+	var colored strings.Builder
+	logger := NewSmartLogger(&colored, "APP", WithClock(testClock), WithColor(ColorAuto), WithAsync(AsyncConfig{}))
+	logger.Error("e")
+	require.NoError(t, logger.Close())
+	assert.Equal(t, "2024-03-15 10:30:45 APP \033[31m[ERROR]\033[0m: e\n", colored.String())
+
+	colored.Reset()
+	logger = NewSmartLogger(&colored, "APP", WithClock(testClock))
+	logger.SetColorMode(ColorAuto)
+	logger.Info("i")
+	logger.SetColorMode(ColorNever)
+	logger.Info("i")
+	assert.Equal(t, "2024-03-15 10:30:45 APP \033[32m[INFO]\033[0m: i\n"+
+		"2024-03-15 10:30:45 APP [INFO]: i\n", colored.String())
+}
+
+func TestColorScopes(t *testing.T) {
+	palette := Palette{Error: "1;31"}
+	tests := []struct {
+		name    string
+		encoder Encoder
+		want    string
+	}{
+		{"text level", TextEncoder{Palette: palette}, "2024-03-15 10:30:45 APP \033[1;31m[ERROR]\033[0m: boom k=v\n"},
+		{"text message", TextEncoder{Palette: palette, ColorScope: ColorMessage}, "2024-03-15 10:30:45 APP [ERROR]: \033[1;31mboom\033[0m k=v\n"},
+		{"text line", TextEncoder{Palette: palette, ColorScope: ColorLine}, "\033[1;31m2024-03-15 10:30:45 APP [ERROR]: boom k=v\033[0m\n"},
+		{"pattern level", MustPatternEncoder(Layout{Pattern: "{level} {msg} {fields}"}), "\033[31mERROR\033[0m boom k=v\n"},
+		{"pattern message", MustPatternEncoder(Layout{Pattern: "{level} {msg} {fields}", ColorScope: ColorMessage}), "ERROR \033[31mboom\033[0m k=v\n"},
+		{"pattern line", MustPatternEncoder(Layout{Pattern: "{level} {msg}", Palette: palette, ColorScope: ColorLine}), "\033[1;31mERROR boom\033[0m\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger("APP", WithEncoder(tt.encoder))
+			logger.EnableColor()
+			logger.Errorw("boom", "k", "v")
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestPaletteFallback(t *testing.T) {
+	palette := Palette{Info: "34"}
+	assert.Equal(t, "34", palette.code(Info))
+	assert.Equal(t, "31", palette.code(Error))
+	assert.Equal(t, "37", palette.code(Level(42)))
+	assert.Equal(t, "33", Palette(nil).code(Warn))
+}
+
+func TestWriterSinkColorMode(t *testing.T) {
+	clearColorEnv(t)
+	logger, _ := newTestLogger("APP")
+	var extra strings.Builder
+	sink := NewWriterSink(&extra, Info, nil)
+	sink.SetColorMode(ColorAuto)
+	logger.AddSink(sink)
+
+	logger.Warn("w")
+	assert.Equal(t, "2024-03-15 10:30:45 APP [WARN]: w\n", extra.String())
+}
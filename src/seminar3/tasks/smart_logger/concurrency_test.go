@@ -0,0 +1,92 @@
+package smartlogger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkedWriter имитирует неатомарный writer: каждая запись дробится
+// на отдельные байты, поэтому без синхронизации строки перемешиваются.
+type chunkedWriter struct {
+	buf strings.Builder
+}
+
+func (w *chunkedWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		w.buf.WriteByte(b)
+	}
+	return len(p), nil
+}
+
+func TestConcurrentLogging(t *testing.T) {
+	const (
+		goroutines = 16
+		perWorker  = 200
+	)
+
+	output := &chunkedWriter{}
+	logger := NewSmartLogger(output, "RACE", WithClock(testClock))
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				switch i % 3 {
+				case 0:
+					logger.Info("worker %d message %d", id, i)
+				case 1:
+					logger.Warn("worker %d message %d", id, i)
+				default:
+					logger.Write([]byte("через Write\n"))
+				}
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(output.buf.String(), "\n"), "\n")
+	require.Len(t, lines, goroutines*perWorker)
+	for _, line := range lines {
+		require.True(t, strings.HasPrefix(line, "2024-03-15 10:30:45 RACE ["), "строка повреждена: %q", line)
+	}
+
+	// записи через Write учитываются наравне с Info и Warn
+	assert.Equal(t, goroutines*perWorker, logger.GetLogCount())
+	assert.Equal(t, int64(output.buf.Len()), logger.Stats().Bytes)
+}
+
+func TestConcurrentConfiguration(t *testing.T) {
+	logger := NewSmartLogger(&chunkedWriter{}, "RACE")
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				logger.Error("error %d", i)
+				logger.Info("info %d", i)
+			}
+		}()
+		go func(id int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				logger.SetLevel(Level(i % 3))
+				logger.EnableColor()
+				logger.Reset()
+				_ = logger.String()
+				_ = logger.GoString()
+				_ = logger.GetLogCount()
+			}
+		}(g)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,45 @@
+package smartlogger
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+)
+
+// loggerKey ключ логгера в context.Context
+type loggerKey struct{}
+
+var defaultLogger atomic.Pointer[SmartLogger]
+
+func init() {
+	defaultLogger.Store(NewSmartLogger(os.Stderr, "", WithColor(ColorAuto)))
+}
+
+// Default возвращает логгер по умолчанию: os.Stderr, цвета в режиме ColorAuto
+func Default() *SmartLogger {
+	return defaultLogger.Load()
+}
+
+// SetDefault заменяет логгер по умолчанию, который FromContext возвращает
+// для контекста без логгера
+func SetDefault(logger *SmartLogger) {
+	defaultLogger.Store(logger)
+}
+
+// NewContext возвращает копию ctx, в которой хранится logger.
+// Обычно туда кладут дочерний логгер с полями запроса:
+//
+//	ctx = smartlogger.NewContext(ctx, logger.With("request_id", id))
+func NewContext(ctx context.Context, logger *SmartLogger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext возвращает логгер из ctx или Default, если его там нет.
+// Возвращенный логгер передает ctx хукам, как после WithContext.
+func FromContext(ctx context.Context) *SmartLogger {
+	logger, ok := ctx.Value(loggerKey{}).(*SmartLogger)
+	if !ok || logger == nil {
+		logger = Default()
+	}
+	return logger.WithContext(ctx)
+}
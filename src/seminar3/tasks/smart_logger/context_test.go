@@ -0,0 +1,47 @@
+package smartlogger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// handle имитирует код глубоко в стеке вызовов, которому доступен только ctx
+func handle(ctx context.Context) {
+	FromContext(ctx).Infow("handled", "step", 2)
+}
+
+func TestContextLogger(t *testing.T) {
+	logger, buf := newTestLogger("APP")
+	ctx := NewContext(context.Background(), logger.With("request_id", "req-1"))
+
+	handle(ctx)
+
+	assert.Equal(t, "2024-03-15 10:30:45 APP [INFO]: handled request_id=req-1 step=2\n", buf.String())
+}
+
+func TestContextLoggerFallsBackToDefault(t *testing.T) {
+	fallback, buf := newTestLogger("DEFAULT")
+	previous := Default()
+	SetDefault(fallback)
+	t.Cleanup(func() { SetDefault(previous) })
+
+	handle(context.Background())
+
+	assert.Equal(t, "2024-03-15 10:30:45 DEFAULT [INFO]: handled step=2\n", buf.String())
+}
+
+func TestContextLoggerPassesContextToHooks(t *testing.T) {
+	logger, buf := newTestLogger("APP", WithHook(ContextValueHook("user", requestIDKey{})))
+	ctx := NewContext(context.Background(), logger)
+	ctx = context.WithValue(ctx, requestIDKey{}, "bob")
+
+	handle(ctx)
+
+	assert.Equal(t, "2024-03-15 10:30:45 APP [INFO]: handled step=2 user=bob\n", buf.String())
+}
+
+func TestDefaultLogger(t *testing.T) {
+	assert.NotNil(t, Default())
+}
@@ -0,0 +1,310 @@
+package smartlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Ключи служебных полей в структурных форматах
+const (
+	TimeKey    = "time"
+	LevelKey   = "level"
+	PrefixKey  = "prefix"
+	MessageKey = "msg"
+	CallerKey  = "caller"
+	StackKey   = "stack"
+)
+
+// Encoder сериализует запись в одну строку, завершенную '\n'
+// (TextEncoder дописывает стек вызовов отдельными строками).
+// color сообщает, что вывод поддерживает ANSI цвета; структурные форматы его игнорируют.
+type Encoder interface {
+	Encode(buf *bytes.Buffer, entry *Entry, color bool) error
+}
+
+// TextEncoder человекочитаемый формат: "timestamp prefix [LEVEL] caller: message key=value".
+// Стек, если он есть, выводится на следующих строках.
+// Произвольный порядок полей задается PatternEncoder.
+type TextEncoder struct {
+	TimeLayout string     // layout для time.Format или EpochMillis, по умолчанию "2006-01-02 15:04:05"
+	UTC        bool       // переводить время в UTC
+	Palette    Palette    // цвета уровней, по умолчанию DefaultPalette
+	ColorScope ColorScope // что раскрашивать при цветном выводе, по умолчанию уровень
+}
+
+func (e TextEncoder) Encode(buf *bytes.Buffer, entry *Entry, color bool) error {
+	start := buf.Len()
+	code := e.Palette.code(entry.Level)
+	if !entry.Time.IsZero() {
+		layout := e.TimeLayout
+		if layout == "" {
+			layout = defaultTextTimeLayout
+		}
+		buf.WriteString(formatTime(entry.Time, layout, e.UTC))
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(entry.Prefix)
+	buf.WriteByte(' ')
+	if color && e.ColorScope == ColorLevel {
+		writeColored(buf, code, "["+entry.Level.String()+"]")
+	} else {
+		fmt.Fprintf(buf, "[%s]", entry.Level)
+	}
+	if entry.Caller != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(entry.Caller)
+	}
+	buf.WriteString(": ")
+	if color && e.ColorScope == ColorMessage {
+		writeColored(buf, code, entry.Message)
+	} else {
+		buf.WriteString(entry.Message)
+	}
+	writeFlatFields(buf, "", entry.Fields, func(key string) string { return key })
+	if color && e.ColorScope == ColorLine {
+		colorLine(buf, start, code)
+	}
+	buf.WriteByte('\n')
+	if entry.Stack != "" {
+		buf.WriteString(entry.Stack)
+		buf.WriteByte('\n')
+	}
+	return nil
+}
+
+// colorLine оборачивает в цвет все, что записано в buf начиная со start
+func colorLine(buf *bytes.Buffer, start int, code string) {
+	line := string(buf.Bytes()[start:])
+	buf.Truncate(start)
+	writeColored(buf, code, line)
+}
+
+// JSONEncoder пишет по одному JSON объекту на строку.
+// Группы полей становятся вложенными объектами.
+// Невалидные UTF-8 байты заменяются на U+FFFD.
+type JSONEncoder struct {
+	TimeLayout string // layout для time.Format или EpochMillis (число), по умолчанию time.RFC3339Nano
+	UTC        bool   // переводить время в UTC
+}
+
+func (e JSONEncoder) Encode(buf *bytes.Buffer, entry *Entry, _ bool) error {
+	buf.WriteByte('{')
+	if !entry.Time.IsZero() {
+		writeJSONString(buf, TimeKey)
+		buf.WriteByte(':')
+		if e.TimeLayout == EpochMillis {
+			buf.WriteString(formatTime(entry.Time, EpochMillis, e.UTC))
+		} else {
+			writeJSONString(buf, formatTime(entry.Time, structuredTimeLayout(e.TimeLayout), e.UTC))
+		}
+		buf.WriteByte(',')
+	}
+
+	writeJSONString(buf, LevelKey)
+	buf.WriteByte(':')
+	writeJSONString(buf, entry.Level.String())
+
+	if entry.Prefix != "" {
+		buf.WriteByte(',')
+		writeJSONString(buf, PrefixKey)
+		buf.WriteByte(':')
+		writeJSONString(buf, entry.Prefix)
+	}
+
+	if entry.Caller != "" {
+		buf.WriteByte(',')
+		writeJSONString(buf, CallerKey)
+		buf.WriteByte(':')
+		writeJSONString(buf, entry.Caller)
+	}
+
+	buf.WriteByte(',')
+	writeJSONString(buf, MessageKey)
+	buf.WriteByte(':')
+	writeJSONString(buf, entry.Message)
+
+	writeJSONFields(buf, entry.Fields, true)
+
+	if entry.Stack != "" {
+		buf.WriteByte(',')
+		writeJSONString(buf, StackKey)
+		buf.WriteByte(':')
+		writeJSONString(buf, entry.Stack)
+	}
+	buf.WriteString("}\n")
+	return nil
+}
+
+// structuredTimeLayout возвращает layout времени для структурных форматов
+func structuredTimeLayout(layout string) string {
+	if layout == "" {
+		return time.RFC3339Nano
+	}
+	return layout
+}
+
+// writeJSONFields пишет поля как члены JSON объекта, группы раскрываются во вложенные объекты
+func writeJSONFields(buf *bytes.Buffer, fields []Field, needComma bool) {
+	for _, field := range fields {
+		if needComma {
+			buf.WriteByte(',')
+		}
+		needComma = true
+
+		writeJSONString(buf, field.Key)
+		buf.WriteByte(':')
+		if group, ok := field.Value.([]Field); ok {
+			buf.WriteByte('{')
+			writeJSONFields(buf, group, false)
+			buf.WriteByte('}')
+			continue
+		}
+		writeJSONValue(buf, field.Value)
+	}
+}
+
+// writeJSONValue сохраняет тип значения: числа и bool остаются числами и bool,
+// ошибки, длительности и время записываются строками.
+func writeJSONValue(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case string:
+		writeJSONString(buf, v)
+	case error:
+		writeJSONString(buf, v.Error())
+	case time.Duration:
+		writeJSONString(buf, v.String())
+	case time.Time:
+		writeJSONString(buf, v.Format(time.RFC3339Nano))
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case int:
+		buf.WriteString(strconv.Itoa(v))
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		writeJSONFloat(buf, v)
+	default:
+		var tmp bytes.Buffer
+		encoder := json.NewEncoder(&tmp)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(v); err != nil {
+			writeJSONString(buf, fmt.Sprint(v))
+			return
+		}
+		buf.Write(bytes.TrimRight(tmp.Bytes(), "\n"))
+	}
+}
+
+func writeJSONFloat(buf *bytes.Buffer, f float64) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		// NaN и бесконечности не представимы в JSON
+		writeJSONString(buf, strconv.FormatFloat(f, 'g', -1, 64))
+		return
+	}
+	buf.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+const hexDigits = "0123456789abcdef"
+
+// writeJSONString экранирует строку по правилам JSON без HTML экранирования
+func writeJSONString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(c)
+			case c == '\n':
+				buf.WriteString(`\n`)
+			case c == '\r':
+				buf.WriteString(`\r`)
+			case c == '\t':
+				buf.WriteString(`\t`)
+			case c < 0x20 || c == 0x7f:
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[c>>4])
+				buf.WriteByte(hexDigits[c&0xf])
+			default:
+				buf.WriteByte(c)
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf.WriteString(`\ufffd`)
+		} else {
+			buf.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	buf.WriteByte('"')
+}
+
+// LogfmtEncoder пишет записи в формате logfmt: key=value через пробел.
+// Ключи полей из групп записываются через точку: group.key=value.
+// Значения с пробелами, кавычками, '=' или управляющими символами
+// заключаются в кавычки и экранируются.
+type LogfmtEncoder struct {
+	TimeLayout string // layout для time.Format или EpochMillis, по умолчанию time.RFC3339Nano
+	UTC        bool   // переводить время в UTC
+}
+
+func (e LogfmtEncoder) Encode(buf *bytes.Buffer, entry *Entry, _ bool) error {
+	if !entry.Time.IsZero() {
+		writeLogfmtPair(buf, TimeKey, formatTime(entry.Time, structuredTimeLayout(e.TimeLayout), e.UTC))
+		buf.WriteByte(' ')
+	}
+	writeLogfmtPair(buf, LevelKey, entry.Level.String())
+	if entry.Prefix != "" {
+		buf.WriteByte(' ')
+		writeLogfmtPair(buf, PrefixKey, entry.Prefix)
+	}
+	if entry.Caller != "" {
+		buf.WriteByte(' ')
+		writeLogfmtPair(buf, CallerKey, entry.Caller)
+	}
+	buf.WriteByte(' ')
+	writeLogfmtPair(buf, MessageKey, entry.Message)
+
+	writeFlatFields(buf, "", entry.Fields, logfmtKey)
+	if entry.Stack != "" {
+		buf.WriteByte(' ')
+		writeLogfmtPair(buf, StackKey, entry.Stack)
+	}
+	buf.WriteByte('\n')
+	return nil
+}
+
+func writeLogfmtPair(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	buf.WriteByte('=')
+	buf.WriteString(quoteIfNeeded(value))
+}
+
+// logfmtKey заменяет в ключе символы, недопустимые в logfmt, на '_'
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	if !needsQuoting(key) {
+		return key
+	}
+	b := []byte(key)
+	for i, c := range b {
+		if c <= ' ' || c == '=' || c == '"' || c >= utf8.RuneSelf {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,84 @@
+package smartlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONEncoder(t *testing.T) {
+	logger, buf := newTestLogger("APP", WithEncoder(JSONEncoder{}))
+
+	logger.Infow("request done",
+		"city", "Moscow",
+		"status", 200,
+		"ratio", 0.5,
+		"ok", true,
+		"latency", 1500*time.Millisecond,
+		"err", errors.New("boom"),
+		"tags", []string{"a", "b"},
+		"nothing", nil,
+	)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(buf.String()), &decoded))
+	assert.Equal(t, map[string]interface{}{
+		"time":    testTime.Format(time.RFC3339Nano),
+		"level":   "INFO",
+		"prefix":  "APP",
+		"msg":     "request done",
+		"city":    "Moscow",
+		"status":  200.0,
+		"ratio":   0.5,
+		"ok":      true,
+		"latency": "1.5s",
+		"err":     "boom",
+		"tags":    []interface{}{"a", "b"},
+		"nothing": nil,
+	}, decoded)
+	assert.True(t, strings.HasSuffix(buf.String(), "}\n"))
+}
+
+func TestJSONEscaping(t *testing.T) {
+	logger, buf := newTestLogger("APP", WithEncoder(JSONEncoder{}))
+
+	logger.Infow("line1\nline2 \"quoted\" <tag> \\ \t\x01", "bad", "a\xffb")
+
+	line := buf.String()
+	assert.Equal(t, 1, strings.Count(line, "\n"), "запись занимает ровно одну строку")
+	assert.Contains(t, line, `"msg":"line1\nline2 \"quoted\" <tag> \\ \t\u0001"`)
+	assert.Contains(t, line, `"bad":"a\ufffdb"`)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(line), &decoded))
+	assert.Equal(t, "line1\nline2 \"quoted\" <tag> \\ \t\x01", decoded["msg"])
+	assert.Equal(t, "a\ufffdb", decoded["bad"])
+}
+
+func TestLogfmtEncoder(t *testing.T) {
+	logger, buf := newTestLogger("APP", WithEncoder(LogfmtEncoder{}))
+
+	logger.Warnw("disk almost full", "used", 95, "path", "/var/log", "note", "line1\nline2", "raw", "a\xffb", "bad key", "v")
+
+	expected := "time=" + testTime.Format(time.RFC3339Nano) +
+		` level=WARN prefix=APP msg="disk almost full" used=95 path=/var/log note="line1\nline2" raw="a\xffb" bad_key=v` + "\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestTextEncoderIsDefault(t *testing.T) {
+	var explicit, implicit bytes.Buffer
+	entry := &Entry{Time: testTime, Level: Warn, Prefix: "APP", Message: "msg", Fields: []Field{F("k", "v")}}
+
+	require.NoError(t, TextEncoder{}.Encode(&explicit, entry, false))
+	logger := NewSmartLogger(&implicit, "APP", WithClock(testClock))
+	logger.Warnw("msg", "k", "v")
+
+	assert.Equal(t, explicit.String(), implicit.String())
+	assert.Equal(t, "2024-03-15 10:30:45 APP [WARN]: msg k=v\n", explicit.String())
+}
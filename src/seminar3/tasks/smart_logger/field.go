@@ -0,0 +1,127 @@
+package smartlogger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// badKey ключ для значений без пары или с нестроковым ключом
+const badKey = "!BADKEY"
+
+// Field структурированное поле записи.
+// Value хранится как есть, чтобы структурные кодировщики сохраняли тип.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F создает поле. Удобно, когда поле передается в Infow или With готовым.
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Group создает поле-группу. Структурные кодировщики выводят ее вложенным объектом,
+// текстовые раскрывают в ключи вида group.key.
+func Group(key string, fields ...Field) Field {
+	return Field{Key: key, Value: fields}
+}
+
+// String возвращает значение поля в текстовом виде.
+// Значения с пробелами, кавычками или знаком '=' заключаются в кавычки.
+func (f Field) String() string {
+	var s string
+	switch v := f.Value.(type) {
+	case string:
+		s = v
+	case error:
+		s = v.Error()
+	case time.Time:
+		s = v.Format(time.RFC3339)
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	return quoteIfNeeded(s)
+}
+
+// Entry одна запись лога
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Prefix  string
+	Message string
+	Fields  []Field
+	Caller  string // "dir/file.go:line", если включен WithCaller
+	Stack   string // стек вызовов, если включен WithStacktrace
+
+	pc  uintptr         // место вызова для передачи в slog.Handler
+	ctx context.Context // контекст для хуков
+}
+
+// fieldsFromArgs превращает список ключ-значение в поля.
+// Готовые Field принимаются без ключа; значение без пары
+// или с нестроковым ключом записывается под ключом !BADKEY.
+func fieldsFromArgs(args []interface{}) []Field {
+	if len(args) == 0 {
+		return nil
+	}
+
+	fields := make([]Field, 0, len(args)/2+1)
+	for i := 0; i < len(args); i++ {
+		switch key := args[i].(type) {
+		case Field:
+			fields = append(fields, key)
+		case string:
+			if i+1 == len(args) {
+				fields = append(fields, Field{Key: badKey, Value: key})
+				continue
+			}
+			fields = append(fields, Field{Key: key, Value: args[i+1]})
+			i++
+		default:
+			fields = append(fields, Field{Key: badKey, Value: key})
+		}
+	}
+	return fields
+}
+
+// writeFlatFields пишет поля как " key=value", раскрывая группы в ключи через точку
+func writeFlatFields(buf *bytes.Buffer, group string, fields []Field, formatKey func(string) string) {
+	for _, field := range fields {
+		key := field.Key
+		if group != "" {
+			key = group + "." + key
+		}
+		if sub, ok := field.Value.([]Field); ok {
+			writeFlatFields(buf, key, sub, formatKey)
+			continue
+		}
+
+		buf.WriteByte(' ')
+		buf.WriteString(formatKey(key))
+		buf.WriteByte('=')
+		buf.WriteString(field.String())
+	}
+}
+
+func quoteIfNeeded(s string) string {
+	if needsQuoting(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func needsQuoting(s string) bool {
+	if s == "" || !utf8.ValidString(s) {
+		return true
+	}
+	return strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r)
+	}) >= 0
+}
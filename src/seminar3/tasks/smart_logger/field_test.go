@@ -0,0 +1,57 @@
+package smartlogger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructuredFields(t *testing.T) {
+	logger, buf := newTestLogger("API")
+
+	logger.Infow("request done", "city", "Moscow", "latency", 1500*time.Millisecond, "status", 200)
+	logger.Warnw("slow", "query", "SELECT 1", "empty", "")
+	logger.Errorw("failed", "err", errors.New("connection refused"))
+
+	expected := "2024-03-15 10:30:45 API [INFO]: request done city=Moscow latency=1.5s status=200\n" +
+		"2024-03-15 10:30:45 API [WARN]: slow query=\"SELECT 1\" empty=\"\"\n" +
+		"2024-03-15 10:30:45 API [ERROR]: failed err=\"connection refused\"\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWith(t *testing.T) {
+	logger, buf := newTestLogger("API")
+	child := logger.With("request_id", "abc")
+	grandchild := child.With(F("user", 42))
+
+	grandchild.Infow("handled", "path", "/weather")
+	child.Info("printf %s", "style")
+	logger.Infow("parent")
+
+	expected := "2024-03-15 10:30:45 API [INFO]: handled request_id=abc user=42 path=/weather\n" +
+		"2024-03-15 10:30:45 API [INFO]: printf style request_id=abc\n" +
+		"2024-03-15 10:30:45 API [INFO]: parent\n"
+	assert.Equal(t, expected, buf.String())
+
+	assert.Equal(t, 3, logger.GetLogCount(), "дочерние логгеры разделяют счетчик")
+	logger.SetLevel(Error)
+	grandchild.Infow("filtered")
+	assert.Equal(t, 3, logger.GetLogCount(), "дочерние логгеры разделяют уровень")
+}
+
+func TestFieldsFromArgs(t *testing.T) {
+	d := 2 * time.Second
+	fields := fieldsFromArgs([]interface{}{"latency", d, F("typed", 3.5), 42, "dangling"})
+
+	assert.Equal(t, []Field{
+		{Key: "latency", Value: d},
+		{Key: "typed", Value: 3.5},
+		{Key: badKey, Value: 42},
+		{Key: badKey, Value: "dangling"},
+	}, fields)
+
+	_, isDuration := fields[0].Value.(time.Duration)
+	assert.True(t, isDuration, "значения сохраняют исходный тип")
+}
@@ -0,0 +1,319 @@
+package smartlogger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Hook обрабатывает запись перед выводом: может добавить или изменить поля
+// и сообщение. Если хук возвращает false, запись отбрасывается и следующие
+// хуки не вызываются. Запись уже содержит префикс и поля логгера.
+//
+// Хуки вызываются по порядку под мьютексом логгера, поэтому не должны
+// писать в тот же логгер.
+type Hook func(ctx context.Context, entry *Entry) bool
+
+// WithHook добавляет хуки в конец цепочки
+func WithHook(hooks ...Hook) Option {
+	return func(sl *SmartLogger) {
+		sl.hooks = append(sl.hooks, hooks...)
+	}
+}
+
+// AddHook добавляет хук в конец цепочки работающего логгера
+func (sl *SmartLogger) AddHook(hook Hook) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.hooks = append(sl.hooks, hook)
+}
+
+// WithContext возвращает дочерний логгер, передающий ctx хукам.
+// Записи через slog получают контекст из вызова slog.
+func (sl *SmartLogger) WithContext(ctx context.Context) *SmartLogger {
+	child := sl.clone()
+	child.ctx = ctx
+	return child
+}
+
+// runHooks прогоняет запись через цепочку хуков. Вызывается под мьютексом.
+func (sl *SmartLogger) runHooks(entry *Entry) bool {
+	if len(sl.hooks) == 0 {
+		return true
+	}
+	ctx := entry.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for _, hook := range sl.hooks {
+		if !hook(ctx, entry) {
+			return false
+		}
+	}
+	return true
+}
+
+// HostnameHook добавляет к записям поле host с именем машины
+func HostnameHook() Hook {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return func(_ context.Context, entry *Entry) bool {
+		entry.Fields = append(entry.Fields, F("host", host))
+		return true
+	}
+}
+
+// ContextValueHook добавляет поле field со значением ctx.Value(key),
+// если оно есть в контексте (например, идентификатор запроса)
+func ContextValueHook(field string, key interface{}) Hook {
+	return func(ctx context.Context, entry *Entry) bool {
+		if value := ctx.Value(key); value != nil {
+			entry.Fields = append(entry.Fields, F(field, value))
+		}
+		return true
+	}
+}
+
+// DropHook отбрасывает записи, для которых match возвращает true,
+// например отладочные записи шумного модуля
+func DropHook(match func(entry *Entry) bool) Hook {
+	return func(_ context.Context, entry *Entry) bool {
+		return !match(entry)
+	}
+}
+
+// Замена скрытых значений по умолчанию
+const defaultRedactMask = "[REDACTED]"
+
+// Глубина, до которой просматриваются вложенные структуры и map
+const maxRedactDepth = 8
+
+// DefaultRedactKeys подстроки имен полей, значения которых скрываются по умолчанию
+var DefaultRedactKeys = []string{"apikey", "api_key", "api-key", "password", "passwd", "secret", "token", "authorization"}
+
+// emailPattern упрощенный шаблон адреса электронной почты
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// RedactConfig что скрывать в записях
+type RedactConfig struct {
+	// Keys подстроки имен полей (без учета регистра), значения которых
+	// скрываются целиком. Они же ищутся в сообщении в виде "key=value"
+	// и "key: value", а также среди имен полей структур и ключей map
+	// в значениях полей, включая приватные поля вроде Config.apiKey.
+	// По умолчанию DefaultRedactKeys.
+	Keys []string
+
+	// Secrets известные секретные значения, например Config.GetAPIKey().
+	// Скрываются везде, в том числе внутри структур в значениях полей.
+	Secrets []string
+
+	Patterns []*regexp.Regexp // выражения, совпадения с которыми скрываются
+	Emails   bool             // скрывать адреса электронной почты
+	Mask     string           // замена, по умолчанию "[REDACTED]"
+}
+
+// redactor скомпилированная RedactConfig
+type redactor struct {
+	keys     []string
+	inline   *regexp.Regexp // key=value в тексте сообщения
+	secrets  []string
+	patterns []*regexp.Regexp
+	mask     string
+}
+
+// RedactHook возвращает хук, скрывающий секреты в сообщении и полях.
+// Значения полей копируются, поэтому поля дочерних логгеров не меняются.
+func RedactHook(config RedactConfig) Hook {
+	r := &redactor{mask: config.Mask, patterns: config.Patterns}
+	if r.mask == "" {
+		r.mask = defaultRedactMask
+	}
+
+	keys := config.Keys
+	if keys == nil {
+		keys = DefaultRedactKeys
+	}
+	quoted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		r.keys = append(r.keys, strings.ToLower(key))
+		quoted = append(quoted, regexp.QuoteMeta(key))
+	}
+	if len(quoted) > 0 {
+		r.inline = regexp.MustCompile(`(?i)([\w.-]*(?:` + strings.Join(quoted, "|") + `)[\w.-]*)(\s*[=:]\s*)("[^"]*"|'[^']*'|[^\s,;]+)`)
+	}
+
+	for _, secret := range config.Secrets {
+		if secret != "" {
+			r.secrets = append(r.secrets, secret)
+		}
+	}
+	if config.Emails {
+		r.patterns = append(append([]*regexp.Regexp(nil), r.patterns...), emailPattern)
+	}
+
+	return func(_ context.Context, entry *Entry) bool {
+		entry.Message = r.redactText(entry.Message)
+		entry.Fields = r.redactFields(entry.Fields)
+		return true
+	}
+}
+
+// redactText скрывает в s известные секреты, совпадения с шаблонами
+// и значения в парах key=value с чувствительными ключами
+func (r *redactor) redactText(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, r.mask)
+	}
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllLiteralString(s, r.mask)
+	}
+	if r.inline != nil {
+		s = r.inline.ReplaceAllString(s, "${1}${2}"+strings.ReplaceAll(r.mask, "$", "$$"))
+	}
+	return s
+}
+
+func (r *redactor) sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range r.keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactFields возвращает копию полей со скрытыми значениями
+func (r *redactor) redactFields(fields []Field) []Field {
+	if len(fields) == 0 {
+		return fields
+	}
+	result := make([]Field, len(fields))
+	for i, field := range fields {
+		result[i] = Field{Key: field.Key, Value: r.redactValue(field.Key, field.Value)}
+	}
+	return result
+}
+
+func (r *redactor) redactValue(key string, value interface{}) interface{} {
+	if group, ok := value.([]Field); ok {
+		return r.redactFields(group)
+	}
+	if r.sensitiveKey(key) {
+		return r.mask
+	}
+
+	switch v := value.(type) {
+	case nil, bool, int, int64, float64, time.Duration, time.Time:
+		return value
+	case string:
+		return r.redactText(v)
+	}
+
+	// структура или map с чувствительными полями превращается в группу,
+	// в которой эти поля скрыты; остальные поля выводятся как есть
+	if group, ok := r.redactReflect(reflect.ValueOf(value), 0); ok {
+		return group
+	}
+
+	// ошибки и прочие значения проверяются по текстовому виду
+	if len(r.secrets) == 0 && len(r.patterns) == 0 && r.inline == nil {
+		return value
+	}
+	text := fmt.Sprint(value)
+	if redacted := r.redactText(text); redacted != text {
+		return redacted
+	}
+	return value
+}
+
+// redactReflect раскладывает структуру или map со строковыми ключами на поля.
+// Возвращает false, если ни одно поле не скрыто по имени: тогда значение
+// выводится как есть.
+func (r *redactor) redactReflect(v reflect.Value, depth int) ([]Field, bool) {
+	if len(r.keys) == 0 || depth > maxRedactDepth {
+		return nil, false
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+
+	var fields []Field
+	redacted := false
+	add := func(name string, fv reflect.Value) {
+		if r.sensitiveKey(name) {
+			fields = append(fields, Field{Key: name, Value: r.mask})
+			redacted = true
+			return
+		}
+		if group, ok := r.redactReflect(fv, depth+1); ok {
+			fields = append(fields, Field{Key: name, Value: group})
+			redacted = true
+			return
+		}
+		fields = append(fields, Field{Key: name, Value: r.plainValue(fv)})
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			add(t.Field(i).Name, v.Field(i))
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			add(key.String(), v.MapIndex(key))
+		}
+	default:
+		return nil, false
+	}
+	return fields, redacted
+}
+
+// plainValue достает значение поля, в том числе приватного: reflect не дает
+// вызвать Interface у таких полей, поэтому простые типы копируются по виду
+// с сохранением типа (time.Duration остается Duration), а остальные
+// выводятся текстом
+func (r *redactor) plainValue(v reflect.Value) interface{} {
+	if v.CanInterface() {
+		if s, ok := v.Interface().(string); ok {
+			return r.redactText(s)
+		}
+		return v.Interface()
+	}
+
+	copied := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		return r.redactText(v.String())
+	case reflect.Bool:
+		copied.SetBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		copied.SetInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		copied.SetUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		copied.SetFloat(v.Float())
+	default:
+		return r.redactText(fmt.Sprint(v))
+	}
+	return copied.Interface()
+}
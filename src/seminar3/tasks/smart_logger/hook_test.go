@@ -0,0 +1,176 @@
+package smartlogger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type requestIDKey struct{}
+
+// apiConfig повторяет Config из practice/structs: ключ хранится в приватном поле
+type apiConfig struct {
+	apiKey  string
+	timeout time.Duration
+}
+
+func TestHooksRunInOrder(t *testing.T) {
+	var calls []string
+	hook := func(name string) Hook {
+		return func(_ context.Context, entry *Entry) bool {
+			calls = append(calls, name)
+			entry.Fields = append(entry.Fields, F("by", name))
+			return true
+		}
+	}
+	logger, buf := newTestLogger("APP", WithHook(hook("first"), hook("second")))
+	logger.With("k", "v").Info("hello")
+
+	assert.Equal(t, []string{"first", "second"}, calls)
+	assert.Equal(t, "2024-03-15 10:30:45 APP [INFO]: hello k=v by=first by=second\n", buf.String())
+}
+
+func TestHookDropsEntry(t *testing.T) {
+	var reached bool
+	logger, buf := newTestLogger("APP", WithHook(
+		DropHook(func(entry *Entry) bool { return entry.Level < Warn && strings.HasPrefix(entry.Message, "poll") }),
+		func(context.Context, *Entry) bool { reached = true; return true },
+	))
+
+	logger.Info("poll tick")
+	assert.False(t, reached, "после отбрасывания следующие хуки не вызываются")
+
+	logger.Warn("poll failed")
+	logger.Info("started")
+
+	assert.Equal(t, "2024-03-15 10:30:45 APP [WARN]: poll failed\n"+
+		"2024-03-15 10:30:45 APP [INFO]: started\n", buf.String())
+	assert.Equal(t, 2, logger.GetLogCount())
+	assert.Equal(t, 1, logger.Stats().Filtered)
+}
+
+func TestContextValueHook(t *testing.T) {
+	logger, buf := newTestLogger("APP", WithHook(ContextValueHook("request_id", requestIDKey{})))
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-42")
+
+	logger.WithContext(ctx).Info("handled")
+	logger.Info("no context")
+	slog.New(NewSlogHandler(logger)).InfoContext(ctx, "via slog")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "2024-03-15 10:30:45 APP [INFO]: handled request_id=req-42", lines[0])
+	assert.Equal(t, "2024-03-15 10:30:45 APP [INFO]: no context", lines[1])
+	// slog ставит собственное время записи
+	assert.True(t, strings.HasSuffix(lines[2], " APP [INFO]: via slog request_id=req-42"), lines[2])
+}
+
+func TestHostnameHook(t *testing.T) {
+	host, err := os.Hostname()
+	require.NoError(t, err)
+
+	logger, buf := newTestLogger("APP", WithHook(HostnameHook()))
+	logger.Info("up")
+	assert.Equal(t, "2024-03-15 10:30:45 APP [INFO]: up host="+quoteIfNeeded(host)+"\n", buf.String())
+}
+
+func TestRedactHookKeys(t *testing.T) {
+	logger, buf := newTestLogger("APP", WithHook(RedactHook(RedactConfig{})))
+
+	logger.With("apiKey", "sk-123").Infow("login",
+		"user", "bob",
+		"Password", "hunter2",
+		Group("http", F("authorization", "Bearer abc")),
+	)
+	logger.Info("connecting with api_key=sk-456, token: 'xyz' retries=3")
+
+	assert.Equal(t, "2024-03-15 10:30:45 APP [INFO]: login apiKey=[REDACTED] user=bob Password=[REDACTED] http.authorization=[REDACTED]\n"+
+		"2024-03-15 10:30:45 APP [INFO]: connecting with api_key=[REDACTED], token: [REDACTED] retries=3\n", buf.String())
+}
+
+func TestRedactHookDoesNotMutateLoggerFields(t *testing.T) {
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "APP", WithClock(testClock))
+	child := logger.With(Group("db", F("password", "p4ss")))
+
+	logger.AddHook(RedactHook(RedactConfig{}))
+	child.Info("first")
+
+	assert.Equal(t, "p4ss", child.fields[0].Value.([]Field)[0].Value)
+	assert.Contains(t, buf.String(), "db.password=[REDACTED]")
+}
+
+func TestRedactHookSecretsEmailsPatterns(t *testing.T) {
+	config := &apiConfig{apiKey: "sk-live-987", timeout: 30 * time.Second}
+	logger, buf := newTestLogger("APP", WithHook(RedactHook(RedactConfig{
+		Keys:     []string{},
+		Secrets:  []string{config.apiKey},
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`ghp_[A-Za-z0-9]+`)},
+		Emails:   true,
+		Mask:     "***",
+	})))
+
+	logger.Infow("config loaded", "config", config, "err", errors.New("bad key sk-live-987"))
+	logger.Info("notify ops@example.com with ghp_AbC123")
+	logger.Infow("custom keys off", "password", "visible")
+
+	assert.Equal(t, "2024-03-15 10:30:45 APP [INFO]: config loaded config=\"&{*** 30000000000}\" err=\"bad key ***\"\n"+
+		"2024-03-15 10:30:45 APP [INFO]: notify *** with ***\n"+
+		"2024-03-15 10:30:45 APP [INFO]: custom keys off password=visible\n", buf.String())
+}
+
+func TestRedactHookStructFields(t *testing.T) {
+	logger, buf := newTestLogger("APP", WithHook(RedactHook(RedactConfig{})))
+	config := &apiConfig{apiKey: "sk-live-987", timeout: 30 * time.Second}
+
+	logger.Infow("config loaded", "config", config)
+	logger.Infow("login", "creds", map[string]string{"user": "bob", "password": "hunter2"})
+	logger.Infow("plain", "user", struct{ Name string }{"bob"})
+
+	assert.Equal(t, "2024-03-15 10:30:45 APP [INFO]: config loaded config.apiKey=[REDACTED] config.timeout=30s\n"+
+		"2024-03-15 10:30:45 APP [INFO]: login creds.password=[REDACTED] creds.user=bob\n"+
+		"2024-03-15 10:30:45 APP [INFO]: plain user={bob}\n", buf.String())
+	assert.NotContains(t, buf.String(), "sk-live")
+}
+
+func TestRedactHookNestedStructJSON(t *testing.T) {
+	type database struct {
+		Host     string
+		Password string
+	}
+	type settings struct {
+		Name string
+		DB   *database
+	}
+
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "", WithEncoder(JSONEncoder{}), WithHook(RedactHook(RedactConfig{})))
+	logger.Infow("settings", "settings", settings{Name: "weather", DB: &database{Host: "db", Password: "hunter2"}})
+
+	records := parseJSONLines(t, []byte(buf.String()))
+	require.Len(t, records, 1)
+	assert.Equal(t, map[string]any{
+		"Name": "weather",
+		"DB":   map[string]any{"Host": "db", "Password": "[REDACTED]"},
+	}, records[0]["settings"])
+}
+
+func TestRedactHookJSON(t *testing.T) {
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "", WithEncoder(JSONEncoder{}), WithHook(RedactHook(RedactConfig{})))
+
+	logger.Infow("login", "token", "abc", "attempt", 2)
+
+	records := parseJSONLines(t, []byte(buf.String()))
+	require.Len(t, records, 1)
+	assert.Equal(t, "[REDACTED]", records[0]["token"])
+	assert.Equal(t, 2.0, records[0]["attempt"])
+}
@@ -0,0 +1,208 @@
+package smartlogger
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EpochMillis вместо layout для time.Format: время пишется числом
+// миллисекунд с начала эпохи Unix
+const EpochMillis = "epochms"
+
+// Формат времени TextEncoder по умолчанию
+const defaultTextTimeLayout = "2006-01-02 15:04:05"
+
+// DefaultPattern шаблон, повторяющий формат TextEncoder без места вызова
+const DefaultPattern = "{time} {prefix} [{level}]: {msg} {fields}"
+
+// Поля, доступные в шаблоне PatternEncoder
+var patternTokens = []string{"time", "level", "prefix", "caller", "msg", "fields"}
+
+// formatTime записывает t по layout (или EpochMillis), при utc — в UTC
+func formatTime(t time.Time, layout string, utc bool) string {
+	if utc {
+		t = t.UTC()
+	}
+	if layout == EpochMillis {
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	}
+	return t.Format(layout)
+}
+
+// Layout параметры PatternEncoder
+type Layout struct {
+	// Pattern шаблон строки, например "{time} {level} [{prefix}] {msg}".
+	// Поля: {time}, {level}, {prefix}, {caller}, {msg}, {fields};
+	// {{ и }} означают литеральные фигурные скобки. По умолчанию DefaultPattern.
+	Pattern    string
+	TimeLayout string     // layout для time.Format или EpochMillis, по умолчанию "2006-01-02 15:04:05"
+	UTC        bool       // переводить время в UTC
+	Palette    Palette    // цвета уровней, по умолчанию DefaultPalette
+	ColorScope ColorScope // что раскрашивать при цветном выводе, по умолчанию уровень
+}
+
+// patternPart литеральный текст или поле записи
+type patternPart struct {
+	literal string
+	token   string
+}
+
+// PatternEncoder текстовый формат с настраиваемым шаблоном строки.
+// Пустые значения (например, префикс или поля) выводятся как пустая строка,
+// пробелы в конце строки отбрасываются. Стек, если он есть, выводится
+// на следующих строках, как в TextEncoder.
+type PatternEncoder struct {
+	layout Layout
+	parts  []patternPart
+}
+
+// NewPatternEncoder проверяет шаблон и возвращает кодировщик.
+// Ошибка описывает первую проблему в шаблоне и ее позицию.
+func NewPatternEncoder(layout Layout) (*PatternEncoder, error) {
+	if layout.Pattern == "" {
+		layout.Pattern = DefaultPattern
+	}
+	if layout.TimeLayout == "" {
+		layout.TimeLayout = defaultTextTimeLayout
+	}
+
+	parts, err := parsePattern(layout.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &PatternEncoder{layout: layout, parts: parts}, nil
+}
+
+// MustPatternEncoder как NewPatternEncoder, но паникует при ошибке.
+// Удобен для шаблонов, заданных в коде.
+func MustPatternEncoder(layout Layout) *PatternEncoder {
+	encoder, err := NewPatternEncoder(layout)
+	if err != nil {
+		panic(err)
+	}
+	return encoder
+}
+
+// PatternError ошибка в шаблоне PatternEncoder
+type PatternError struct {
+	Pattern string
+	Pos     int // позиция в байтах
+	Msg     string
+}
+
+func (e *PatternError) Error() string {
+	return fmt.Sprintf("неверный шаблон %q (позиция %d): %s", e.Pattern, e.Pos, e.Msg)
+}
+
+func parsePattern(pattern string) ([]patternPart, error) {
+	var parts []patternPart
+	var literal strings.Builder
+	hasMessage := false
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '{' && strings.HasPrefix(pattern[i:], "{{"):
+			literal.WriteByte('{')
+			i++
+		case c == '}' && strings.HasPrefix(pattern[i:], "}}"):
+			literal.WriteByte('}')
+			i++
+		case c == '}':
+			return nil, &PatternError{Pattern: pattern, Pos: i, Msg: "лишняя '}', для литеральной скобки используйте '}}'"}
+		case c == '{':
+			end := strings.IndexAny(pattern[i+1:], "{}")
+			if end < 0 || pattern[i+1+end] != '}' {
+				return nil, &PatternError{Pattern: pattern, Pos: i, Msg: "незакрытая '{', для литеральной скобки используйте '{{'"}
+			}
+			token := pattern[i+1 : i+1+end]
+			if !isPatternToken(token) {
+				return nil, &PatternError{Pattern: pattern, Pos: i,
+					Msg: fmt.Sprintf("неизвестное поле {%s}, допустимы: {%s}", token, strings.Join(patternTokens, "}, {"))}
+			}
+			if token == "msg" {
+				hasMessage = true
+			}
+			if literal.Len() > 0 {
+				parts = append(parts, patternPart{literal: literal.String()})
+				literal.Reset()
+			}
+			parts = append(parts, patternPart{token: token})
+			i += end + 1
+		default:
+			literal.WriteByte(c)
+		}
+	}
+
+	if literal.Len() > 0 {
+		parts = append(parts, patternPart{literal: literal.String()})
+	}
+	if !hasMessage {
+		return nil, &PatternError{Pattern: pattern, Pos: len(pattern), Msg: "в шаблоне нет поля {msg}"}
+	}
+	return parts, nil
+}
+
+func isPatternToken(token string) bool {
+	for _, t := range patternTokens {
+		if t == token {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *PatternEncoder) Encode(buf *bytes.Buffer, entry *Entry, color bool) error {
+	start := buf.Len()
+	code := e.layout.Palette.code(entry.Level)
+	for _, part := range e.parts {
+		switch part.token {
+		case "":
+			buf.WriteString(part.literal)
+		case "time":
+			if !entry.Time.IsZero() {
+				buf.WriteString(formatTime(entry.Time, e.layout.TimeLayout, e.layout.UTC))
+			}
+		case "level":
+			if color && e.layout.ColorScope == ColorLevel {
+				writeColored(buf, code, entry.Level.String())
+			} else {
+				buf.WriteString(entry.Level.String())
+			}
+		case "prefix":
+			buf.WriteString(entry.Prefix)
+		case "caller":
+			buf.WriteString(entry.Caller)
+		case "msg":
+			if color && e.layout.ColorScope == ColorMessage {
+				writeColored(buf, code, entry.Message)
+			} else {
+				buf.WriteString(entry.Message)
+			}
+		case "fields":
+			fieldsStart := buf.Len()
+			writeFlatFields(buf, "", entry.Fields, func(key string) string { return key })
+			if buf.Len() > fieldsStart {
+				// writeFlatFields ставит пробел перед каждым полем, первый не нужен
+				b := buf.Bytes()
+				copy(b[fieldsStart:], b[fieldsStart+1:])
+				buf.Truncate(buf.Len() - 1)
+			}
+		}
+	}
+
+	line := bytes.TrimRight(buf.Bytes()[start:], " ")
+	buf.Truncate(start + len(line))
+	if color && e.layout.ColorScope == ColorLine {
+		colorLine(buf, start, code)
+	}
+	buf.WriteByte('\n')
+	if entry.Stack != "" {
+		buf.WriteString(entry.Stack)
+		buf.WriteByte('\n')
+	}
+	return nil
+}
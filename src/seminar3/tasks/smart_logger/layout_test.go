@@ -0,0 +1,119 @@
+package smartlogger
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternEncoder(t *testing.T) {
+	encoder, err := NewPatternEncoder(Layout{Pattern: "{time} {level} [{prefix}] {msg} {fields}"})
+	require.NoError(t, err)
+	logger, buf := newTestLogger("APP", WithEncoder(encoder))
+
+	logger.Infow("request done", "city", "Moscow", "status", 200)
+	logger.Warn("без полей")
+
+	assert.Equal(t, "2024-03-15 10:30:45 INFO [APP] request done city=Moscow status=200\n"+
+		"2024-03-15 10:30:45 WARN [APP] без полей\n", buf.String())
+}
+
+func TestPatternEncoderDefaultMatchesText(t *testing.T) {
+	encoder, err := NewPatternEncoder(Layout{})
+	require.NoError(t, err)
+	patternLogger, patternBuf := newTestLogger("APP", WithEncoder(encoder))
+	textLogger, textBuf := newTestLogger("APP", WithEncoder(TextEncoder{}))
+
+	for _, logger := range []*SmartLogger{patternLogger, textLogger} {
+		logger.Errorw("boom", "attempt", 3, "req", Group("http", F("method", "GET")))
+		logger.Info("plain")
+	}
+
+	assert.Equal(t, textBuf.String(), patternBuf.String())
+}
+
+func TestPatternEncoderOmitsFields(t *testing.T) {
+	logger, buf := newTestLogger("APP", WithEncoder(MustPatternEncoder(Layout{Pattern: "{level}: {msg}"})))
+	logger.Infow("hello", "k", "v")
+	assert.Equal(t, "INFO: hello\n", buf.String())
+}
+
+func TestPatternEncoderLiteralBraces(t *testing.T) {
+	logger, buf := newTestLogger("APP", WithEncoder(MustPatternEncoder(Layout{Pattern: "{{{level}}} {msg}"})))
+	logger.Info("hello")
+	assert.Equal(t, "{INFO} hello\n", buf.String())
+}
+
+func TestPatternEncoderCallerAndColor(t *testing.T) {
+	encoder := MustPatternEncoder(Layout{Pattern: "{level} {caller} {msg}"})
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "", WithEncoder(encoder), WithCaller())
+	logger.EnableColor()
+
+	want := nextLine()
+	logger.Warn("slow")
+
+	assert.Equal(t, "\033[33mWARN\033[0m "+want+" slow\n", buf.String())
+}
+
+func TestPatternErrors(t *testing.T) {
+	tests := []struct {
+		pattern string
+		pos     int
+		msg     string
+	}{
+		{"{time} {lvl} {msg}", 7, "неизвестное поле {lvl}, допустимы: {time}, {level}, {prefix}, {caller}, {msg}, {fields}"},
+		{"{time {msg}", 0, "незакрытая '{', для литеральной скобки используйте '{{'"},
+		{"{msg", 0, "незакрытая '{', для литеральной скобки используйте '{{'"},
+		{"{msg} }", 6, "лишняя '}', для литеральной скобки используйте '}}'"},
+		{"{time} {level}", 14, "в шаблоне нет поля {msg}"},
+		{"{} {msg}", 0, "неизвестное поле {}, допустимы: {time}, {level}, {prefix}, {caller}, {msg}, {fields}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			_, err := NewPatternEncoder(Layout{Pattern: tt.pattern})
+			var patternErr *PatternError
+			require.True(t, errors.As(err, &patternErr), "ошибка %v", err)
+			assert.Equal(t, tt.pos, patternErr.Pos)
+			assert.Equal(t, tt.msg, patternErr.Msg)
+			assert.Contains(t, err.Error(), strconv.Quote(tt.pattern))
+		})
+	}
+
+	assert.Panics(t, func() { MustPatternEncoder(Layout{Pattern: "{oops}"}) })
+}
+
+func TestTimeLayouts(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	at := time.Date(2024, 3, 15, 10, 30, 45, 123456789, moscow)
+
+	tests := []struct {
+		name    string
+		encoder Encoder
+		want    string
+	}{
+		{"text default", TextEncoder{}, "2024-03-15 10:30:45  [INFO]: m\n"},
+		{"text utc", TextEncoder{UTC: true}, "2024-03-15 07:30:45  [INFO]: m\n"},
+		{"text rfc3339nano", TextEncoder{TimeLayout: time.RFC3339Nano}, "2024-03-15T10:30:45.123456789+03:00  [INFO]: m\n"},
+		{"text epoch", TextEncoder{TimeLayout: EpochMillis}, "1710487845123  [INFO]: m\n"},
+		{"pattern utc", MustPatternEncoder(Layout{Pattern: "{time} {msg}", TimeLayout: time.RFC3339, UTC: true}), "2024-03-15T07:30:45Z m\n"},
+		{"json default", JSONEncoder{}, `{"time":"2024-03-15T10:30:45.123456789+03:00","level":"INFO","msg":"m"}` + "\n"},
+		{"json epoch", JSONEncoder{TimeLayout: EpochMillis}, `{"time":1710487845123,"level":"INFO","msg":"m"}` + "\n"},
+		{"logfmt utc", LogfmtEncoder{UTC: true}, "time=2024-03-15T07:30:45.123456789Z level=INFO msg=m\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			logger := NewSmartLogger(&buf, "", WithClock(func() time.Time { return at }), WithEncoder(tt.encoder))
+			logger.Info("m")
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
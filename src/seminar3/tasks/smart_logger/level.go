@@ -0,0 +1,79 @@
+package smartlogger
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Level int
+
+// Info остается нулевым значением, поэтому уровень по умолчанию не меняется
+const (
+	Trace Level = iota - 2
+	Debug
+	Info
+	Warn
+	Error
+	Fatal
+)
+
+func (l Level) String() string {
+	switch l {
+	case Trace:
+		return "TRACE"
+	case Debug:
+		return "DEBUG"
+	case Info:
+		return "INFO"
+	case Warn:
+		return "WARN"
+	case Error:
+		return "ERROR"
+	case Fatal:
+		return "FATAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseLevel разбирает имя уровня без учета регистра: "warn", "WARNING", "Error"
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "TRACE":
+		return Trace, nil
+	case "DEBUG":
+		return Debug, nil
+	case "INFO":
+		return Info, nil
+	case "WARN", "WARNING":
+		return Warn, nil
+	case "ERROR":
+		return Error, nil
+	case "FATAL":
+		return Fatal, nil
+	}
+	return Info, fmt.Errorf("неизвестный уровень логирования %q", s)
+}
+
+// MarshalText позволяет использовать Level в JSON и других текстовых форматах
+func (l Level) MarshalText() ([]byte, error) {
+	if l < Trace || l > Fatal {
+		return nil, fmt.Errorf("неизвестный уровень логирования %d", int(l))
+	}
+	return []byte(l.String()), nil
+}
+
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// Set реализует flag.Value, поэтому уровень можно задать флагом:
+// flag.Var(&level, "log-level", "уровень логирования")
+func (l *Level) Set(s string) error {
+	return l.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,153 @@
+package smartlogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+)
+
+// AtomicLevel уровень логирования, который можно менять во время работы
+// без блокировок: логгеры читают его при каждой записи.
+// Один AtomicLevel можно разделить между несколькими логгерами.
+type AtomicLevel struct {
+	level atomic.Int32
+}
+
+func NewAtomicLevel(level Level) *AtomicLevel {
+	l := &AtomicLevel{}
+	l.SetLevel(level)
+	return l
+}
+
+func (l *AtomicLevel) Level() Level {
+	return Level(l.level.Load())
+}
+
+func (l *AtomicLevel) SetLevel(level Level) {
+	l.level.Store(int32(level))
+}
+
+// Enabled сообщает, проходят ли записи уровня level
+func (l *AtomicLevel) Enabled(level Level) bool {
+	return l.Level() <= level
+}
+
+func (l *AtomicLevel) String() string {
+	return l.Level().String()
+}
+
+// levelPayload тело запросов и ответов ServeHTTP
+type levelPayload struct {
+	Level Level `json:"level"`
+}
+
+type levelError struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP позволяет читать и менять уровень по HTTP:
+//
+//	GET                      -> {"level":"INFO"}
+//	PUT {"level":"debug"}    -> {"level":"DEBUG"}
+func (l *AtomicLevel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var payload struct {
+			Level *Level `json:"level"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			writeLevelJSON(w, http.StatusBadRequest, levelError{fmt.Sprintf("некорректный запрос: %v", err)})
+			return
+		}
+		if payload.Level == nil {
+			writeLevelJSON(w, http.StatusBadRequest, levelError{"не указано поле level"})
+			return
+		}
+		l.SetLevel(*payload.Level)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		writeLevelJSON(w, http.StatusMethodNotAllowed, levelError{"поддерживаются только GET и PUT"})
+		return
+	}
+	writeLevelJSON(w, http.StatusOK, levelPayload{l.Level()})
+}
+
+func writeLevelJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// CycleOnSignal при каждом сигнале делает уровень на ступень подробнее:
+// INFO -> DEBUG -> TRACE, а после TRACE возвращается к ERROR.
+// По умолчанию слушает SIGUSR1; на системах без него без явно
+// переданных сигналов ничего не делает. Новый уровень передается
+// в onChange, если он задан. Возвращает функцию остановки.
+func (l *AtomicLevel) CycleOnSignal(onChange func(Level), signals ...os.Signal) (stop func()) {
+	if len(signals) == 0 {
+		signals = defaultCycleSignals
+	}
+	if len(signals) == 0 {
+		return func() {}
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, signals...)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ch:
+				level := l.cycle()
+				if onChange != nil {
+					onChange(level)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+// cycle атомарно переключает уровень на следующий в цикле
+func (l *AtomicLevel) cycle() Level {
+	for {
+		old := l.level.Load()
+		next := nextCycleLevel(Level(old))
+		if l.level.CompareAndSwap(old, int32(next)) {
+			return next
+		}
+	}
+}
+
+// nextCycleLevel возвращает более подробный уровень; FATAL в цикл не входит
+func nextCycleLevel(level Level) Level {
+	if level <= Trace || level > Error {
+		return Error
+	}
+	return level - 1
+}
+
+// WithAtomicLevel задает общий уровень, которым можно управлять извне,
+// например через AtomicLevel.ServeHTTP
+func WithAtomicLevel(level *AtomicLevel) Option {
+	return func(sl *SmartLogger) {
+		sl.level = level
+	}
+}
+
+// AtomicLevel возвращает уровень логгера для изменения во время работы.
+// SetLevel логгера и AtomicLevel().SetLevel равнозначны.
+func (sl *SmartLogger) AtomicLevel() *AtomicLevel {
+	return sl.level
+}
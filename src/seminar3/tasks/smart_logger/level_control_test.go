@@ -0,0 +1,143 @@
+package smartlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func doLevelRequest(t *testing.T, handler http.Handler, method, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, "/loglevel", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAtomicLevelShared(t *testing.T) {
+	level := NewAtomicLevel(Warn)
+	var firstOut strings.Builder
+	first := NewSmartLogger(&firstOut, "A", WithClock(testClock), WithAtomicLevel(level))
+	second := NewSmartLogger(nil, "B", WithAtomicLevel(level))
+
+	first.Info("не проходит")
+	level.SetLevel(Debug)
+	first.Debug("проходит")
+
+	assert.Equal(t, "2024-03-15 10:30:45 A [DEBUG]: проходит\n", firstOut.String())
+	assert.Same(t, level, second.AtomicLevel())
+	assert.True(t, second.enabled(Debug))
+
+	second.SetLevel(Error)
+	assert.Equal(t, Error, level.Level(), "SetLevel логгера меняет общий уровень")
+	assert.Equal(t, "ERROR", level.String())
+}
+
+func TestAtomicLevelHTTP(t *testing.T) {
+	logger, out := newTestLogger("APP")
+	handler := logger.AtomicLevel()
+
+	rec := doLevelRequest(t, handler, http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"level":"INFO"}`, rec.Body.String())
+
+	logger.Debug("скрыто")
+	rec = doLevelRequest(t, handler, http.MethodPut, `{"level":"debug"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"DEBUG"}`, rec.Body.String())
+	logger.Debug("видно")
+
+	assert.Equal(t, "2024-03-15 10:30:45 APP [DEBUG]: видно\n", out.String())
+}
+
+func TestAtomicLevelHTTPErrors(t *testing.T) {
+	level := NewAtomicLevel(Info)
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		status int
+		error  string
+	}{
+		{"неизвестный уровень", http.MethodPut, `{"level":"loud"}`, http.StatusBadRequest, `неизвестный уровень логирования \"loud\"`},
+		{"нет поля", http.MethodPut, `{}`, http.StatusBadRequest, "не указано поле level"},
+		{"не JSON", http.MethodPut, `debug`, http.StatusBadRequest, "некорректный запрос"},
+		{"метод", http.MethodPost, `{"level":"debug"}`, http.StatusMethodNotAllowed, "поддерживаются только GET и PUT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doLevelRequest(t, level, tt.method, tt.body)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.error)
+			assert.Equal(t, Info, level.Level(), "уровень не меняется при ошибке")
+		})
+	}
+
+	rec := doLevelRequest(t, level, http.MethodDelete, "")
+	assert.Equal(t, "GET, PUT", rec.Header().Get("Allow"))
+}
+
+func TestNextCycleLevel(t *testing.T) {
+	level := NewAtomicLevel(Info)
+	var got []Level
+	for i := 0; i < 6; i++ {
+		got = append(got, level.cycle())
+	}
+	assert.Equal(t, []Level{Debug, Trace, Error, Warn, Info, Debug}, got)
+
+	assert.Equal(t, Error, nextCycleLevel(Fatal))
+}
+
+func TestAtomicLevelConcurrent(t *testing.T) {
+	output := &syncBuilder{}
+	logger := NewSmartLogger(output, "RACE", WithClock(testClock))
+	level := logger.AtomicLevel()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				logger.Debug("debug %d", i)
+				logger.Error("error %d", i)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			level.cycle()
+			doLevelRequest(t, level, http.MethodGet, "")
+		}
+	}()
+	wg.Wait()
+
+	// Error проходит на любом уровне цикла
+	assert.Equal(t, 8*200, strings.Count(output.String(), "[ERROR]"))
+}
+
+// syncBuilder strings.Builder с мьютексом для чтения после записи из горутин
+type syncBuilder struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (s *syncBuilder) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuilder) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package smartlogger
+
+import "os"
+
+// SIGUSR1 есть только на unix системах, здесь сигналы нужно передать явно
+var defaultCycleSignals []os.Signal
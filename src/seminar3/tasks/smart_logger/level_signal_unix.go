@@ -0,0 +1,10 @@
+//go:build unix
+
+package smartlogger
+
+import (
+	"os"
+	"syscall"
+)
+
+var defaultCycleSignals = []os.Signal{syscall.SIGUSR1}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package smartlogger
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtomicLevelCycleOnSIGUSR1(t *testing.T) {
+	level := NewAtomicLevel(Info)
+	changes := make(chan Level, 2)
+	stop := level.CycleOnSignal(func(l Level) { changes <- l })
+	defer stop()
+
+	for _, want := range []Level{Debug, Trace} {
+		require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+		select {
+		case got := <-changes:
+			assert.Equal(t, want, got)
+		case <-time.After(time.Second):
+			t.Fatal("уровень не переключился после SIGUSR1")
+		}
+	}
+	assert.Equal(t, Trace, level.Level())
+}
@@ -0,0 +1,128 @@
+package smartlogger
+
+import (
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Level
+	}{
+		{"trace", Trace},
+		{"DEBUG", Debug},
+		{"Info", Info},
+		{"warn", Warn},
+		{"warning", Warn},
+		{" error ", Error},
+		{"fatal", Fatal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			level, err := ParseLevel(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, level)
+		})
+	}
+
+	_, err := ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestLevelText(t *testing.T) {
+	for _, level := range []Level{Trace, Debug, Info, Warn, Error, Fatal} {
+		text, err := level.MarshalText()
+		require.NoError(t, err)
+
+		var parsed Level
+		require.NoError(t, parsed.UnmarshalText(text))
+		assert.Equal(t, level, parsed)
+	}
+
+	_, err := Level(42).MarshalText()
+	assert.Error(t, err)
+}
+
+func TestLevelJSONConfig(t *testing.T) {
+	var config struct {
+		Level Level `json:"level"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"level": "debug"}`), &config))
+	assert.Equal(t, Debug, config.Level)
+
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"level": "DEBUG"}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"level": "loud"}`), &config))
+}
+
+func TestLevelFlag(t *testing.T) {
+	level := Info
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&level, "log-level", "уровень логирования")
+
+	require.NoError(t, fs.Parse([]string{"--log-level", "trace"}))
+	assert.Equal(t, Trace, level)
+	assert.Error(t, fs.Parse([]string{"--log-level", "nope"}))
+}
+
+func TestDebugAndTraceAreOffByDefault(t *testing.T) {
+	logger, buf := newTestLogger("APP")
+
+	logger.Trace("trace")
+	logger.Debugw("debug")
+	assert.Empty(t, buf.String())
+
+	logger.SetLevel(Trace)
+	logger.Tracew("trace", "k", 1)
+	logger.Debug("debug %d", 2)
+
+	expected := "2024-03-15 10:30:45 APP [TRACE]: trace k=1\n" +
+		"2024-03-15 10:30:45 APP [DEBUG]: debug 2\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+type syncRecorder struct {
+	strings.Builder
+	synced bool
+}
+
+func (s *syncRecorder) Sync() error {
+	s.synced = true
+	return nil
+}
+
+func TestFatal(t *testing.T) {
+	output := &syncRecorder{}
+	exitCode := -1
+	logger := NewSmartLogger(output, "APP", WithClock(testClock), WithExitFunc(func(code int) {
+		exitCode = code
+	}))
+
+	logger.Fatal("не удалось запуститься: %s", "порт занят")
+
+	assert.Equal(t, "2024-03-15 10:30:45 APP [FATAL]: не удалось запуститься: порт занят\n", output.String())
+	assert.True(t, output.synced, "Fatal сбрасывает вывод перед завершением")
+	assert.Equal(t, 1, exitCode)
+
+	exitCode = -1
+	logger.Fatalw("fatal", "k", "v")
+	assert.Equal(t, 1, exitCode)
+	assert.Equal(t, 2, logger.GetLogCount())
+}
+
+func TestSlogLevelMapping(t *testing.T) {
+	for _, level := range []Level{Trace, Debug, Info, Warn, Error, Fatal} {
+		assert.Equal(t, level, fromSlogLevel(toSlogLevel(level)), level.String())
+	}
+	assert.Equal(t, Debug, fromSlogLevel(slog.LevelDebug+2))
+}
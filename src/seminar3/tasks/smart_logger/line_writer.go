@@ -0,0 +1,112 @@
+package smartlogger
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Строка длиннее этого предела записывается, не дожидаясь '\n'
+const maxLineLength = 64 * 1024
+
+// WithLevelDetection включает определение уровня строк, пришедших через Write,
+// по префиксу "ERROR:", "[WARN]", "warning:" и т.п. (регистр не важен).
+// Префикс убирается из сообщения, строки без префикса пишутся как Info.
+// Строка с префиксом FATAL пишется уровнем Fatal, но программу не завершает.
+func WithLevelDetection() Option {
+	return func(sl *SmartLogger) {
+		sl.detectLevel = true
+	}
+}
+
+// Write реализует io.Writer: данные буферизуются, и каждая полная строка
+// становится отдельной записью. Незавершенный хвост ждет следующего Write
+// и записывается при Sync, Flush или Close. Поэтому логгер можно передать
+// в log.SetOutput, exec.Cmd.Stderr или log.New для http.Server.ErrorLog.
+// Возвращает len(p), как того требует контракт io.Writer.
+func (sl *SmartLogger) Write(p []byte) (n int, err error) {
+	sl.mu.Lock()
+	defer sl.unlock()
+
+	sl.partial = append(sl.partial, p...)
+	for {
+		i := bytes.IndexByte(sl.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(sl.partial[:i])
+		sl.partial = sl.partial[i+1:]
+		if lineErr := sl.writeLine(line); lineErr != nil && err == nil {
+			err = lineErr
+		}
+	}
+
+	if len(sl.partial) >= maxLineLength {
+		if lineErr := sl.flushPartial(); lineErr != nil && err == nil {
+			err = lineErr
+		}
+	}
+	if len(sl.partial) == 0 {
+		// не держим большой массив после длинной строки
+		sl.partial = nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// flushPartial записывает незавершенную строку из буфера Write.
+// Вызывается под мьютексом.
+func (sl *SmartLogger) flushPartial() error {
+	if len(sl.partial) == 0 {
+		return nil
+	}
+	line := string(sl.partial)
+	sl.partial = nil
+	return sl.writeLine(line)
+}
+
+// writeLine записывает одну строку из Write, пустые строки пропускаются.
+// Вызывается под мьютексом.
+func (sl *SmartLogger) writeLine(line string) error {
+	message := strings.TrimSpace(line)
+	if message == "" {
+		return nil
+	}
+
+	level := Info
+	if sl.detectLevel {
+		level, message = detectLevel(message)
+	}
+	if !sl.level.Enabled(level) {
+		return nil
+	}
+	return sl.writeLocked(&Entry{Time: sl.now(), Level: level, Message: message})
+}
+
+// detectLevel ищет в начале строки уровень вида "LEVEL:" или "[LEVEL]"
+// и возвращает его вместе с сообщением без префикса
+func detectLevel(line string) (Level, string) {
+	var name, rest string
+	if strings.HasPrefix(line, "[") {
+		end := strings.IndexByte(line, ']')
+		if end < 0 {
+			return Info, line
+		}
+		name, rest = line[1:end], line[end+1:]
+		rest = strings.TrimPrefix(rest, ":")
+	} else {
+		end := strings.IndexByte(line, ':')
+		if end < 0 {
+			return Info, line
+		}
+		name, rest = line[:end], line[end+1:]
+	}
+
+	level, err := ParseLevel(name)
+	if err != nil {
+		return Info, line
+	}
+	return level, strings.TrimSpace(rest)
+}
@@ -0,0 +1,162 @@
+package smartlogger
+
+import (
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSplitsLines(t *testing.T) {
+	logger, buf := newTestLogger("IO")
+
+	_, err := logger.Write([]byte("первая\nвторая\r\n\n  \nтретья\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "2024-03-15 10:30:45 IO [INFO]: первая\n"+
+		"2024-03-15 10:30:45 IO [INFO]: вторая\n"+
+		"2024-03-15 10:30:45 IO [INFO]: третья\n", buf.String())
+	assert.Equal(t, 3, logger.GetLogCount())
+}
+
+func TestWriteBuffersPartialLines(t *testing.T) {
+	logger, buf := newTestLogger("IO")
+
+	logger.Write([]byte("начало "))
+	logger.Write([]byte("середина "))
+	assert.Empty(t, buf.String())
+
+	logger.Write([]byte("конец\nхвост"))
+	assert.Equal(t, "2024-03-15 10:30:45 IO [INFO]: начало середина конец\n", buf.String())
+
+	require.NoError(t, logger.Sync())
+	assert.Equal(t, "2024-03-15 10:30:45 IO [INFO]: начало середина конец\n"+
+		"2024-03-15 10:30:45 IO [INFO]: хвост\n", buf.String())
+}
+
+func TestWritePartialFlushedOnClose(t *testing.T) {
+	output := &closeRecorder{}
+	logger := NewSmartLogger(output, "IO", WithClock(testClock))
+
+	logger.Write([]byte("без перевода строки"))
+	require.NoError(t, logger.Close())
+
+	assert.Equal(t, "2024-03-15 10:30:45 IO [INFO]: без перевода строки\n", output.String())
+}
+
+func TestWriteLongLine(t *testing.T) {
+	logger, buf := newTestLogger("IO")
+
+	logger.Write([]byte(strings.Repeat("x", maxLineLength)))
+	assert.Equal(t, 1, logger.GetLogCount(), "длинная строка пишется без ожидания '\\n'")
+
+	logger.Write([]byte("y\n"))
+	assert.Equal(t, 2, logger.GetLogCount())
+	assert.True(t, strings.HasSuffix(buf.String(), "[INFO]: y\n"))
+}
+
+func TestDetectLevel(t *testing.T) {
+	tests := []struct {
+		line    string
+		level   Level
+		message string
+	}{
+		{"ERROR: disk full", Error, "disk full"},
+		{"error:disk full", Error, "disk full"},
+		{"[WARN] slow request", Warn, "slow request"},
+		{"[warning]: slow request", Warn, "slow request"},
+		{"[ DEBUG ] cache miss", Debug, "cache miss"},
+		{"Fatal: boom", Fatal, "boom"},
+		{"TRACE: step", Trace, "step"},
+		{"обычная строка", Info, "обычная строка"},
+		{"note: not a level", Info, "note: not a level"},
+		{"[unclosed bracket", Info, "[unclosed bracket"},
+		{"[main] started", Info, "[main] started"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			level, message := detectLevel(tt.line)
+			assert.Equal(t, tt.level, level)
+			assert.Equal(t, tt.message, message)
+		})
+	}
+}
+
+func TestWriteLevelDetection(t *testing.T) {
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "IO", WithClock(testClock), WithLevelDetection(),
+		WithExitFunc(func(int) { t.Fatal("Write не должен завершать программу") }))
+
+	logger.Write([]byte("DEBUG: скрыто уровнем\n[WARN] медленно\nFATAL: авария\n"))
+
+	assert.Equal(t, "2024-03-15 10:30:45 IO [WARN]: медленно\n"+
+		"2024-03-15 10:30:45 IO [FATAL]: авария\n", buf.String())
+}
+
+func TestWriteWithoutDetectionKeepsPrefix(t *testing.T) {
+	logger, buf := newTestLogger("IO")
+	logger.Write([]byte("ERROR: disk full\n"))
+	assert.Equal(t, "2024-03-15 10:30:45 IO [INFO]: ERROR: disk full\n", buf.String())
+}
+
+func TestWriteAsStdLogOutput(t *testing.T) {
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "STD", WithClock(testClock), WithLevelDetection())
+	std := log.New(logger, "", 0)
+
+	std.Printf("ERROR: подключение к %s не удалось", "db")
+	std.Print("многострочное\nсообщение")
+
+	assert.Equal(t, "2024-03-15 10:30:45 STD [ERROR]: подключение к db не удалось\n"+
+		"2024-03-15 10:30:45 STD [INFO]: многострочное\n"+
+		"2024-03-15 10:30:45 STD [INFO]: сообщение\n", buf.String())
+}
+
+func TestWriteAsCmdStderr(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh недоступен")
+	}
+
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "CMD", WithClock(testClock), WithLevelDetection())
+
+	cmd := exec.Command("sh", "-c", `printf 'WARN: first\nsecond' >&2`)
+	cmd.Stderr = logger
+	require.NoError(t, cmd.Run())
+	require.NoError(t, logger.Sync())
+
+	assert.Equal(t, "2024-03-15 10:30:45 CMD [WARN]: first\n"+
+		"2024-03-15 10:30:45 CMD [INFO]: second\n", buf.String())
+}
+
+func TestWriteAsHTTPServerErrorLog(t *testing.T) {
+	var buf strings.Builder
+	logger := NewSmartLogger(&buf, "HTTP", WithClock(testClock))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("handler failed")
+	}))
+	server.Config.ErrorLog = log.New(logger, "", 0)
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+	}
+	server.Close()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.NotEmpty(t, lines)
+	assert.Contains(t, lines[0], "HTTP [INFO]: http: panic serving")
+	assert.Contains(t, lines[0], "handler failed")
+	for _, line := range lines {
+		assert.True(t, strings.HasPrefix(line, "2024-03-15 10:30:45 HTTP ["), "каждая строка стека — отдельная запись: %q", line)
+	}
+}
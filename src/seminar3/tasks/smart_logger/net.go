@@ -0,0 +1,166 @@
+package smartlogger
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Значения по умолчанию для NetConfig
+const (
+	defaultDialTimeout  = 5 * time.Second
+	defaultWriteTimeout = 5 * time.Second
+	defaultMinBackoff   = 100 * time.Millisecond
+	defaultMaxBackoff   = 30 * time.Second
+)
+
+// ErrNotConnected возвращается, пока NetWriter ждет следующей попытки подключения
+var ErrNotConnected = errors.New("нет соединения с сервером логов")
+
+// NetConfig параметры сетевой записи
+type NetConfig struct {
+	DialTimeout  time.Duration // таймаут подключения, по умолчанию 5s
+	WriteTimeout time.Duration // таймаут записи, по умолчанию 5s
+	MinBackoff   time.Duration // пауза после первой неудачи, по умолчанию 100ms
+	MaxBackoff   time.Duration // максимальная пауза между попытками, по умолчанию 30s
+}
+
+// NetWriter пишет в TCP, UDP или unix сокет. Подключается при первой записи,
+// а после обрыва переподключается с экспоненциальной паузой между попытками.
+// Пока пауза не истекла, Write сразу возвращает ErrNotConnected, не блокируя логгер.
+// Каждый вызов Write отправляется целиком, для UDP — одной датаграммой.
+type NetWriter struct {
+	network string
+	addr    string
+	config  NetConfig
+	now     func() time.Time // часы для пауз между попытками, подменяются в тестах
+	dial    func(network, addr string, timeout time.Duration) (net.Conn, error)
+
+	mu       sync.Mutex
+	conn     net.Conn
+	failures int       // неудачных попыток подряд
+	retryAt  time.Time // раньше этого времени не переподключаемся
+	closed   bool
+}
+
+func NewNetWriter(network, addr string, config NetConfig) *NetWriter {
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = defaultDialTimeout
+	}
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = defaultWriteTimeout
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = defaultMinBackoff
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = max(defaultMaxBackoff, config.MinBackoff)
+	}
+	return &NetWriter{
+		network: network,
+		addr:    addr,
+		config:  config,
+		now:     time.Now,
+		dial:    net.DialTimeout,
+	}
+}
+
+// NewNetSink создает приемник, который кодирует записи encoder
+// и отправляет их на network/addr через NetWriter
+func NewNetSink(network, addr string, level Level, encoder Encoder, config NetConfig) *WriterSink {
+	return NewWriterSink(NewNetWriter(network, addr, config), level, encoder)
+}
+
+// Write отправляет p. Если соединение оборвалось, сразу пробует
+// переподключиться и отправить еще раз: старое соединение могло
+// закрыться, пока логгер молчал.
+func (w *NetWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, errors.New("запись в закрытый NetWriter")
+	}
+
+	hadConn := w.conn != nil
+	n, err := w.write(p)
+	if err == nil || !hadConn {
+		return n, err
+	}
+	w.retryAt = time.Time{}
+	return w.write(p)
+}
+
+func (w *NetWriter) write(p []byte) (int, error) {
+	if err := w.connect(); err != nil {
+		return 0, err
+	}
+	if err := w.conn.SetWriteDeadline(time.Now().Add(w.config.WriteTimeout)); err != nil {
+		w.fail()
+		return 0, err
+	}
+	n, err := w.conn.Write(p)
+	if err != nil {
+		w.fail()
+		return n, fmt.Errorf("ошибка записи в %s://%s: %w", w.network, w.addr, err)
+	}
+	w.failures = 0
+	return n, nil
+}
+
+func (w *NetWriter) connect() error {
+	if w.conn != nil {
+		return nil
+	}
+	if w.now().Before(w.retryAt) {
+		return ErrNotConnected
+	}
+	conn, err := w.dial(w.network, w.addr, w.config.DialTimeout)
+	if err != nil {
+		w.fail()
+		return fmt.Errorf("ошибка подключения к %s://%s: %w", w.network, w.addr, err)
+	}
+	w.conn = conn
+	return nil
+}
+
+// fail закрывает соединение и откладывает следующую попытку
+// подключения, удваивая паузу до MaxBackoff
+func (w *NetWriter) fail() {
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+	w.retryAt = w.now().Add(w.backoff())
+	w.failures++
+}
+
+func (w *NetWriter) backoff() time.Duration {
+	d := w.config.MinBackoff
+	for i := 0; i < w.failures && d < w.config.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, w.config.MaxBackoff)
+}
+
+// Connected сообщает, есть ли сейчас открытое соединение
+func (w *NetWriter) Connected() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.conn != nil
+}
+
+func (w *NetWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
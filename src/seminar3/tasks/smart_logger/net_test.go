@@ -0,0 +1,168 @@
+package smartlogger
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pipeDialer выдает соединения net.Pipe. Все, что приходит
+// на серверные концы, построчно складывается в lines.
+type pipeDialer struct {
+	fail    bool
+	dials   int
+	servers []net.Conn
+	lines   chan string
+}
+
+func (d *pipeDialer) dial(_, _ string, _ time.Duration) (net.Conn, error) {
+	d.dials++
+	if d.fail {
+		return nil, errors.New("connection refused")
+	}
+	client, server := net.Pipe()
+	d.servers = append(d.servers, server)
+	go func() {
+		scanner := bufio.NewScanner(server)
+		for scanner.Scan() {
+			d.lines <- scanner.Text()
+		}
+	}()
+	return client, nil
+}
+
+func newPipeNetWriter(t *testing.T, config NetConfig) (*NetWriter, *pipeDialer, *fakeClock) {
+	t.Helper()
+	dialer := &pipeDialer{lines: make(chan string, 10)}
+	clock := &fakeClock{t: testTime}
+	w := NewNetWriter("tcp", "logs:514", config)
+	w.dial = dialer.dial
+	w.now = clock.Now
+	t.Cleanup(func() {
+		w.Close()
+		for _, server := range dialer.servers {
+			server.Close()
+		}
+	})
+	return w, dialer, clock
+}
+
+func TestNetWriterTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	sink := NewNetSink("tcp", ln.Addr().String(), Info, JSONEncoder{}, NetConfig{})
+	logger := NewSmartLogger(nil, "APP", WithSink(sink), WithClock(testClock))
+	logger.Infow("по сети", "n", 1)
+	logger.Debug("не проходит по уровню")
+	logger.Warn("второе")
+	require.NoError(t, logger.Close())
+
+	assert.Equal(t, `{"time":"2024-03-15T10:30:45Z","level":"INFO","prefix":"APP","msg":"по сети","n":1}`, <-lines)
+	assert.Contains(t, <-lines, `"msg":"второе"`)
+}
+
+func TestNetWriterUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	w := NewNetWriter("udp", conn.LocalAddr().String(), NetConfig{})
+	defer w.Close()
+
+	_, err = w.Write([]byte("first\n"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("second\n"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 1024)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "first\n", string(buf[:n]), "каждая запись — отдельная датаграмма")
+	n, _, err = conn.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "second\n", string(buf[:n]))
+}
+
+func TestNetWriterReconnectsAfterBrokenConnection(t *testing.T) {
+	w, dialer, _ := newPipeNetWriter(t, NetConfig{})
+
+	_, err := w.Write([]byte("one\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "one", <-dialer.lines)
+	assert.True(t, w.Connected())
+
+	// сервер закрыл соединение: запись сразу уходит в новое
+	require.NoError(t, dialer.servers[0].Close())
+	_, err = w.Write([]byte("two\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "two", <-dialer.lines)
+	assert.Equal(t, 2, dialer.dials)
+}
+
+func TestNetWriterBackoff(t *testing.T) {
+	w, dialer, clock := newPipeNetWriter(t, NetConfig{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second})
+	dialer.fail = true
+
+	_, err := w.Write([]byte("a\n"))
+	assert.ErrorContains(t, err, "connection refused")
+	assert.Equal(t, 1, dialer.dials)
+
+	_, err = w.Write([]byte("b\n"))
+	assert.ErrorIs(t, err, ErrNotConnected, "пауза не истекла, подключение не пробуем")
+	assert.Equal(t, 1, dialer.dials)
+
+	clock.Advance(100 * time.Millisecond)
+	_, err = w.Write([]byte("c\n"))
+	assert.ErrorContains(t, err, "connection refused")
+	assert.Equal(t, 2, dialer.dials)
+
+	clock.Advance(150 * time.Millisecond)
+	_, err = w.Write([]byte("d\n"))
+	assert.ErrorIs(t, err, ErrNotConnected, "после второй неудачи пауза удвоилась")
+
+	clock.Advance(50 * time.Millisecond)
+	dialer.fail = false
+	_, err = w.Write([]byte("e\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "e", <-dialer.lines)
+	assert.Equal(t, 3, dialer.dials)
+	assert.Equal(t, 0, w.failures, "успешная запись сбрасывает паузу")
+}
+
+func TestNetWriterBackoffLimit(t *testing.T) {
+	w := NewNetWriter("tcp", "logs:514", NetConfig{MinBackoff: time.Second, MaxBackoff: 10 * time.Second})
+	for failures, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second} {
+		w.failures = failures
+		assert.Equal(t, want, w.backoff())
+	}
+	w.failures = 1000
+	assert.Equal(t, 10*time.Second, w.backoff())
+}
+
+func TestNetWriterClosed(t *testing.T) {
+	w, _, _ := newPipeNetWriter(t, NetConfig{})
+	require.NoError(t, w.Close())
+	_, err := w.Write([]byte("x\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,158 @@
+package smartlogger
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// RingSink приемник, хранящий последние N записей в памяти.
+// Удобен в тестах (см. пакет smartloggertest) и для страницы
+// диагностики "последние ошибки".
+type RingSink struct {
+	mu      sync.Mutex
+	level   Level
+	entries []Entry
+	next    int  // позиция для следующей записи
+	full    bool // буфер заполнен и перезаписывается по кругу
+}
+
+// NewRingSink создает приемник на capacity записей уровня level и выше
+func NewRingSink(capacity int, level Level) *RingSink {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &RingSink{level: level, entries: make([]Entry, capacity)}
+}
+
+func (r *RingSink) Enabled(level Level) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return level >= r.level
+}
+
+// WriteEntry сохраняет копию записи, вытесняя самую старую при переполнении
+func (r *RingSink) WriteEntry(entry *Entry) error {
+	stored := *entry
+	stored.Fields = append([]Field(nil), entry.Fields...)
+	stored.ctx = nil
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = stored
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	return nil
+}
+
+// Entries возвращает сохраненные записи от старых к новым
+func (r *RingSink) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]Entry(nil), r.entries[:r.next]...)
+	}
+	result := make([]Entry, 0, len(r.entries))
+	result = append(result, r.entries[r.next:]...)
+	return append(result, r.entries[:r.next]...)
+}
+
+// Len возвращает число сохраненных записей
+func (r *RingSink) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.full {
+		return len(r.entries)
+	}
+	return r.next
+}
+
+// Reset удаляет все сохраненные записи
+func (r *RingSink) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.entries)
+	r.next = 0
+	r.full = false
+}
+
+// Filter возвращает записи, для которых match возвращает true
+func (r *RingSink) Filter(match func(entry *Entry) bool) []Entry {
+	var result []Entry
+	for _, entry := range r.Entries() {
+		if match(&entry) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// ByLevel возвращает записи уровня level
+func (r *RingSink) ByLevel(level Level) []Entry {
+	return r.Filter(func(entry *Entry) bool { return entry.Level == level })
+}
+
+// Containing возвращает записи, сообщение которых содержит substr
+func (r *RingSink) Containing(substr string) []Entry {
+	return r.Filter(func(entry *Entry) bool { return strings.Contains(entry.Message, substr) })
+}
+
+// WithField возвращает записи, в которых поле key имеет значение value.
+// Ключи полей из групп записываются через точку: "http.method".
+// Значения сравниваются по текстовому виду, поэтому 200 совпадает с int64(200).
+func (r *RingSink) WithField(key string, value interface{}) []Entry {
+	want := fmt.Sprint(value)
+	return r.Filter(func(entry *Entry) bool {
+		got, ok := entry.Lookup(key)
+		return ok && fmt.Sprint(got) == want
+	})
+}
+
+// Lines возвращает записи в компактном виде "LEVEL message key=value"
+// без времени и префикса — удобно сравнивать в тестах
+func (r *RingSink) Lines() []string {
+	entries := r.Entries()
+	lines := make([]string, len(entries))
+	for i := range entries {
+		lines[i] = entries[i].Line()
+	}
+	return lines
+}
+
+// Lookup возвращает значение поля key. Ключи полей из групп
+// записываются через точку: "http.method". При повторе ключа
+// возвращается последнее значение, как его увидит читатель лога.
+func (e *Entry) Lookup(key string) (interface{}, bool) {
+	return lookupField(e.Fields, key)
+}
+
+func lookupField(fields []Field, key string) (value interface{}, found bool) {
+	for _, field := range fields {
+		if group, ok := field.Value.([]Field); ok {
+			if rest, ok := strings.CutPrefix(key, field.Key+"."); ok {
+				if v, ok := lookupField(group, rest); ok {
+					value, found = v, true
+				}
+			}
+			continue
+		}
+		if field.Key == key {
+			value, found = field.Value, true
+		}
+	}
+	return value, found
+}
+
+// Line форматирует запись как "LEVEL message key=value", так же как Lines
+func (e *Entry) Line() string {
+	var buf bytes.Buffer
+	buf.WriteString(e.Level.String())
+	buf.WriteByte(' ')
+	buf.WriteString(e.Message)
+	writeFlatFields(&buf, "", e.Fields, func(key string) string { return key })
+	return buf.String()
+}
@@ -0,0 +1,63 @@
+package smartlogger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingSinkKeepsLastN(t *testing.T) {
+	ring := NewRingSink(3, Info)
+	logger := NewSmartLogger(nil, "APP", WithSink(ring))
+
+	logger.Info("1")
+	logger.Info("2")
+	assert.Equal(t, []string{"INFO 1", "INFO 2"}, ring.Lines())
+
+	logger.Info("3")
+	logger.Info("4")
+	logger.Info("5")
+	assert.Equal(t, []string{"INFO 3", "INFO 4", "INFO 5"}, ring.Lines())
+	assert.Equal(t, 3, ring.Len())
+
+	ring.Reset()
+	assert.Empty(t, ring.Entries())
+	logger.Info("6")
+	assert.Equal(t, []string{"INFO 6"}, ring.Lines())
+}
+
+func TestRingSinkStoresCopies(t *testing.T) {
+	ring := NewRingSink(10, Trace)
+	entry := &Entry{Level: Info, Message: "m", Fields: []Field{F("k", 1)}}
+	require.NoError(t, ring.WriteEntry(entry))
+
+	entry.Message = "changed"
+	entry.Fields[0].Value = 2
+
+	assert.Equal(t, []string{"INFO m k=1"}, ring.Lines())
+}
+
+func TestRingSinkQueries(t *testing.T) {
+	ring := NewRingSink(10, Trace)
+	logger := NewSmartLogger(nil, "", WithSink(ring))
+	logger.SetLevel(Trace)
+	logger = logger.With("service", "weather")
+
+	logger.Debugw("cache miss", "city", "Moscow")
+	logger.Infow("request done", "city", "Moscow", "status", 200, Group("http", F("method", "GET")))
+	logger.Errorw("request failed", "city", "London", "status", int64(502))
+
+	assert.Len(t, ring.ByLevel(Error), 1)
+	assert.Len(t, ring.Containing("request"), 2)
+	assert.Len(t, ring.WithField("city", "Moscow"), 2)
+	assert.Len(t, ring.WithField("status", 502), 1)
+	assert.Len(t, ring.WithField("http.method", "GET"), 1)
+	assert.Len(t, ring.WithField("service", "weather"), 3)
+	assert.Empty(t, ring.WithField("missing", ""))
+
+	entry := ring.ByLevel(Error)[0]
+	value, ok := entry.Lookup("city")
+	assert.True(t, ok)
+	assert.Equal(t, "London", value)
+}
@@ -0,0 +1,377 @@
+package smartlogger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// backupTimeFormat формат метки времени в именах архивных файлов.
+// Лексикографический порядок имен совпадает с хронологическим.
+const backupTimeFormat = "20060102T150405.000"
+
+// RotateConfig параметры ротации RotatingFile
+type RotateConfig struct {
+	MaxSize    int64 // максимальный размер файла в байтах, 0 - без ограничения
+	Daily      bool  // начинать новый файл каждые сутки
+	MaxBackups int   // сколько архивных файлов хранить, 0 - все
+	Compress   bool  // сжимать архивные файлы gzip
+}
+
+// RotatingFile io.WriteCloser, который ротирует файл по размеру и/или по дням.
+// Архивные файлы получают имя вида app-20240315T103045.000.log[.gz].
+// Безопасен для одновременного использования.
+type RotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	config   RotateConfig
+	now      func() time.Time
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	signals  chan os.Signal
+	done     chan struct{}
+	closed   bool // после Close файл больше не открывается
+
+	cleanup    sync.WaitGroup // фоновое сжатие и удаление старых архивов
+	cleanupMu  sync.Mutex     // фоновые задачи выполняются по одной
+	cleanupErr error          // первая ошибка фоновой задачи, возвращается из Close
+}
+
+func NewRotatingFile(path string, config RotateConfig) (*RotatingFile, error) {
+	return newRotatingFile(path, config, time.Now)
+}
+
+func newRotatingFile(path string, config RotateConfig, now func() time.Time) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:   path,
+		config: config,
+		now:    now,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write пишет p в текущий файл, предварительно выполняя ротацию, если нужно.
+// Запись никогда не разбивается между двумя файлами.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	// ротация переименовала файл, но не смогла открыть новый: пробуем снова
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate принудительно переносит текущий файл в архив и начинает новый
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return os.ErrClosed
+	}
+	return f.rotate()
+}
+
+// Reopen закрывает и заново открывает файл по тому же пути.
+// Нужен, когда файл переименовал внешний logrotate.
+// После Close возвращает os.ErrClosed и файл не открывает.
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return os.ErrClosed
+	}
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			return fmt.Errorf("ошибка закрытия файла лога: %w", err)
+		}
+		f.file = nil
+	}
+	return f.open()
+}
+
+// ReopenOnSignal переоткрывает файл при получении сигналов, по умолчанию SIGHUP.
+// Ошибки переоткрытия передаются в onError, если он задан.
+// Обработка сигналов прекращается при Close.
+func (f *RotatingFile) ReopenOnSignal(onError func(error), signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.signals != nil {
+		return
+	}
+
+	f.signals = make(chan os.Signal, 1)
+	f.done = make(chan struct{})
+	signal.Notify(f.signals, signals...)
+
+	go func(ch <-chan os.Signal, done <-chan struct{}) {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ch:
+				// сигнал мог прийти во время Close: закрытый файл не переоткрываем
+				if err := f.Reopen(); err != nil && !errors.Is(err, os.ErrClosed) && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}(f.signals, f.done)
+}
+
+// Sync сбрасывает данные текущего файла на диск
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.file.Sync()
+}
+
+// Close закрывает файл и дожидается фонового сжатия архивов.
+// Ошибки фонового сжатия возвращаются отсюда.
+func (f *RotatingFile) Close() error {
+	err := f.closeFile()
+
+	f.cleanup.Wait()
+	f.cleanupMu.Lock()
+	defer f.cleanupMu.Unlock()
+	err = errors.Join(err, f.cleanupErr)
+	f.cleanupErr = nil
+	return err
+}
+
+func (f *RotatingFile) closeFile() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	if f.signals != nil {
+		signal.Stop(f.signals)
+		close(f.done)
+		f.signals = nil
+	}
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// Вспомогательные методы, вызываются под мьютексом
+
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("ошибка создания каталога лога: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла лога: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("ошибка чтения информации о файле лога: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.now()
+	if f.size > 0 {
+		// Файл остался с прошлого запуска: сутки отсчитываются от его изменения
+		f.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+func (f *RotatingFile) shouldRotate(writeSize int64) bool {
+	if f.config.MaxSize > 0 && f.size > 0 && f.size+writeSize > f.config.MaxSize {
+		return true
+	}
+	if f.config.Daily && f.size > 0 {
+		y1, m1, d1 := f.openedAt.Date()
+		y2, m2, d2 := f.now().Date()
+		return y1 != y2 || m1 != m2 || d1 != d2
+	}
+	return false
+}
+
+func (f *RotatingFile) rotate() error {
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			return fmt.Errorf("ошибка закрытия файла лога: %w", err)
+		}
+		f.file = nil
+	}
+
+	backup := f.backupName()
+	if err := os.Rename(f.path, backup); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("ошибка переименования файла лога: %w", err)
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	if !f.config.Compress {
+		return f.removeOldBackups()
+	}
+
+	// Сжатие может занять заметное время, а запись в лог идет под мьютексом
+	// логгера, поэтому архив сжимается в фоне уже после открытия нового файла.
+	// Старые архивы удаляются после сжатия, чтобы не считать файл дважды.
+	f.cleanup.Add(1)
+	go func() {
+		defer f.cleanup.Done()
+		f.cleanupMu.Lock()
+		defer f.cleanupMu.Unlock()
+
+		err := compressFile(backup)
+		if err == nil {
+			err = f.removeOldBackups()
+		}
+		if err != nil && f.cleanupErr == nil {
+			f.cleanupErr = err
+		}
+	}()
+	return nil
+}
+
+// backupName возвращает свободное имя архивного файла
+func (f *RotatingFile) backupName() string {
+	dir, base, ext := f.splitPath()
+	stamp := f.now().Format(backupTimeFormat)
+
+	name := filepath.Join(dir, fmt.Sprintf("%s-%s%s", base, stamp, ext))
+	for i := 1; fileExists(name) || fileExists(name+".gz"); i++ {
+		name = filepath.Join(dir, fmt.Sprintf("%s-%s-%d%s", base, stamp, i, ext))
+	}
+	return name
+}
+
+func (f *RotatingFile) splitPath() (dir, base, ext string) {
+	dir = filepath.Dir(f.path)
+	ext = filepath.Ext(f.path)
+	base = strings.TrimSuffix(filepath.Base(f.path), ext)
+	return dir, base, ext
+}
+
+// backups возвращает архивные файлы от старых к новым
+func (f *RotatingFile) backups() ([]string, error) {
+	dir, base, ext := f.splitPath()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения каталога лога: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		stamp, ok := strings.CutPrefix(name, base+"-")
+		if entry.IsDir() || !ok || len(stamp) < len(backupTimeFormat) {
+			continue
+		}
+		if _, err := time.Parse(backupTimeFormat, stamp[:len(backupTimeFormat)]); err != nil {
+			continue
+		}
+		if strings.HasSuffix(name, ext) || strings.HasSuffix(name, ext+".gz") {
+			names = append(names, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (f *RotatingFile) removeOldBackups() error {
+	if f.config.MaxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := f.backups()
+	if err != nil {
+		return err
+	}
+	for len(backups) > f.config.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return fmt.Errorf("ошибка удаления старого файла лога: %w", err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// compressFile сжимает файл в path.gz и удаляет исходный
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла для сжатия: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("ошибка создания сжатого файла: %w", err)
+	}
+	defer func() {
+		if closeErr := dst.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		return fmt.Errorf("ошибка сжатия файла лога: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("ошибка сжатия файла лога: %w", err)
+	}
+
+	src.Close()
+	return os.Remove(path)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,239 @@
+package smartlogger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock часы, которые двигаются только вручную
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func newTestRotatingFile(t *testing.T, config RotateConfig) (*RotatingFile, *fakeClock, string) {
+	t.Helper()
+	dir := t.TempDir()
+	clock := &fakeClock{t: testTime}
+
+	f, err := newRotatingFile(filepath.Join(dir, "app.log"), config, clock.Now)
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+	return f, clock, dir
+}
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestRotatingFileBySize(t *testing.T) {
+	f, clock, dir := newTestRotatingFile(t, RotateConfig{MaxSize: 11})
+
+	_, err := f.Write([]byte("12345\n"))
+	require.NoError(t, err)
+	_, err = f.Write([]byte("1234\n"))
+	require.NoError(t, err)
+
+	clock.Advance(time.Second)
+	_, err = f.Write([]byte("abcdef\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"app-20240315T103046.000.log", "app.log"}, listDir(t, dir))
+	assert.Equal(t, "12345\n1234\n", readFile(t, filepath.Join(dir, "app-20240315T103046.000.log")))
+	assert.Equal(t, "abcdef\n", readFile(t, filepath.Join(dir, "app.log")))
+}
+
+func TestRotatingFileOversizedWrite(t *testing.T) {
+	f, _, dir := newTestRotatingFile(t, RotateConfig{MaxSize: 4})
+
+	_, err := f.Write([]byte("longer than max\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"app.log"}, listDir(t, dir), "пустой файл не ротируется")
+}
+
+func TestRotatingFileMaxBackups(t *testing.T) {
+	f, clock, dir := newTestRotatingFile(t, RotateConfig{MaxBackups: 2})
+
+	for i := 0; i < 4; i++ {
+		clock.Advance(time.Second)
+		_, err := f.Write([]byte("line\n"))
+		require.NoError(t, err)
+		require.NoError(t, f.Rotate())
+	}
+
+	assert.Equal(t, []string{
+		"app-20240315T103048.000.log",
+		"app-20240315T103049.000.log",
+		"app.log",
+	}, listDir(t, dir))
+}
+
+func TestRotatingFileDaily(t *testing.T) {
+	f, clock, dir := newTestRotatingFile(t, RotateConfig{Daily: true})
+
+	_, err := f.Write([]byte("day 1\n"))
+	require.NoError(t, err)
+
+	clock.Advance(time.Hour)
+	_, err = f.Write([]byte("day 1 later\n"))
+	require.NoError(t, err)
+	assert.Len(t, listDir(t, dir), 1)
+
+	clock.Advance(24 * time.Hour)
+	_, err = f.Write([]byte("day 2\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"app-20240316T113045.000.log", "app.log"}, listDir(t, dir))
+	assert.Equal(t, "day 2\n", readFile(t, filepath.Join(dir, "app.log")))
+}
+
+func TestRotatingFileCompress(t *testing.T) {
+	f, _, dir := newTestRotatingFile(t, RotateConfig{Compress: true})
+
+	_, err := f.Write([]byte("compressed line\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Rotate())
+	f.cleanup.Wait()
+
+	assert.Equal(t, []string{"app-20240315T103045.000.log.gz", "app.log"}, listDir(t, dir))
+
+	gzFile, err := os.Open(filepath.Join(dir, "app-20240315T103045.000.log.gz"))
+	require.NoError(t, err)
+	defer gzFile.Close()
+	reader, err := gzip.NewReader(gzFile)
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "compressed line\n", string(content))
+}
+
+func TestRotatingFileCompressInBackground(t *testing.T) {
+	f, clock, dir := newTestRotatingFile(t, RotateConfig{MaxSize: 10, MaxBackups: 1, Compress: true})
+
+	// пока фоновое сжатие занято, запись в новый файл не ждет его
+	f.cleanupMu.Lock()
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+		clock.Advance(time.Second)
+	}
+	assert.Equal(t, []string{"app-20240315T103046.000.log", "app-20240315T103047.000.log", "app.log"}, listDir(t, dir))
+	f.cleanupMu.Unlock()
+
+	require.NoError(t, f.Close())
+	assert.Equal(t, []string{"app-20240315T103047.000.log.gz", "app.log"}, listDir(t, dir))
+}
+
+func TestRotatingFileWriteRetriesFailedOpen(t *testing.T) {
+	f, _, dir := newTestRotatingFile(t, RotateConfig{})
+	path := filepath.Join(dir, "app.log")
+
+	// так выглядит файл, если после переименования в rotate не удалось открыть новый
+	require.NoError(t, f.file.Close())
+	f.file = nil
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, os.Mkdir(path, 0o755))
+
+	_, err := f.Write([]byte("lost\n"))
+	assert.ErrorContains(t, err, "ошибка открытия файла лога")
+	assert.NotErrorIs(t, err, os.ErrClosed)
+
+	require.NoError(t, os.Remove(path))
+	_, err = f.Write([]byte("after\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "after\n", readFile(t, path))
+}
+
+func TestRotatingFileIgnoresForeignFiles(t *testing.T) {
+	f, _, dir := newTestRotatingFile(t, RotateConfig{MaxBackups: 1})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app-errors.log"), []byte("x"), 0o644))
+
+	_, err := f.Write([]byte("line\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Rotate())
+
+	assert.Contains(t, listDir(t, dir), "app-errors.log")
+}
+
+func TestRotatingFileReopen(t *testing.T) {
+	f, _, dir := newTestRotatingFile(t, RotateConfig{})
+	path := filepath.Join(dir, "app.log")
+
+	_, err := f.Write([]byte("before\n"))
+	require.NoError(t, err)
+
+	// Внешний logrotate переименовал файл
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, f.Reopen())
+
+	_, err = f.Write([]byte("after\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "before\n", readFile(t, path+".1"))
+	assert.Equal(t, "after\n", readFile(t, path))
+}
+
+func TestRotatingFileReopenAfterClose(t *testing.T) {
+	f, _, dir := newTestRotatingFile(t, RotateConfig{})
+	require.NoError(t, f.Close())
+	require.NoError(t, os.Remove(filepath.Join(dir, "app.log")))
+
+	assert.ErrorIs(t, f.Reopen(), os.ErrClosed)
+	assert.ErrorIs(t, f.Rotate(), os.ErrClosed)
+	_, err := f.Write([]byte("после Close\n"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+	assert.Empty(t, listDir(t, dir), "закрытый файл не создается заново")
+}
+
+func TestRotatingFileWithLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := NewRotatingFile(path, RotateConfig{MaxSize: 1024})
+	require.NoError(t, err)
+
+	logger := NewSmartLogger(f, "APP", WithClock(testClock))
+	logger.Info("в файл")
+	require.NoError(t, logger.Close())
+
+	_, err = f.Write([]byte("x"))
+	assert.ErrorIs(t, err, os.ErrClosed, "Close логгера закрывает файл")
+	assert.True(t, strings.HasSuffix(readFile(t, path), "APP [INFO]: в файл\n"))
+}
+
+func TestCloseSkipsStandardStreams(t *testing.T) {
+	for _, stream := range []*os.File{os.Stdout, os.Stderr} {
+		logger := NewSmartLogger(stream, "APP")
+		require.NoError(t, logger.Close())
+		_, err := stream.Stat()
+		assert.NoError(t, err, stream.Name())
+	}
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package smartlogger
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFileReopenOnSIGHUP(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f, err := NewRotatingFile(path, RotateConfig{})
+	require.NoError(t, err)
+	defer f.Close()
+
+	f.ReopenOnSignal(func(err error) { t.Error(err) })
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, 10*time.Millisecond, "файл должен быть создан заново после SIGHUP")
+}
@@ -0,0 +1,170 @@
+package smartlogger
+
+import (
+	"fmt"
+	"time"
+)
+
+// Интервал выборки по умолчанию для SamplingConfig
+const defaultSamplingInterval = time.Second
+
+// SamplingConfig параметры выборки сообщений. Ключ выборки — уровень
+// и текст сообщения: в каждом интервале пропускаются первые Initial
+// сообщений с одинаковым ключом, а затем только каждое Thereafter-е.
+// Если Initial и Thereafter оба равны нулю, выборка выключена и работает
+// только схлопывание повторов.
+type SamplingConfig struct {
+	Initial    int           // сколько сообщений с одним ключом пропускать за интервал без выборки
+	Thereafter int           // затем пропускать каждое M-е; 0 — отбрасывать все остальные
+	Interval   time.Duration // длина интервала, по умолчанию 1s
+
+	// CollapseDuplicates схлопывает подряд идущие одинаковые записи:
+	// повторы не пишутся, а перед следующей отличающейся записью (или при
+	// Sync, Flush и Close) выводится одна строка с числом повторов.
+	CollapseDuplicates bool
+}
+
+// sampleKey ключ, по которому считаются сообщения в интервале
+type sampleKey struct {
+	level   Level
+	message string
+}
+
+// sampler хранит состояние выборки и схлопывания повторов.
+// Используется только под мьютексом логгера.
+type sampler struct {
+	config      SamplingConfig
+	windowStart time.Time
+	counts      map[sampleKey]int
+
+	last      *Entry // последняя записанная запись, с которой сравниваются повторы
+	lastKey   string
+	repeats   int
+	sampled   int // отброшено выборкой
+	collapsed int // схлопнуто как повторы
+}
+
+func newSampler(config SamplingConfig) *sampler {
+	if config.Interval <= 0 {
+		config.Interval = defaultSamplingInterval
+	}
+	return &sampler{config: config, counts: make(map[sampleKey]int)}
+}
+
+// WithSampling включает выборку сообщений и, по желанию, схлопывание повторов
+func WithSampling(config SamplingConfig) Option {
+	return func(sl *SmartLogger) {
+		sl.sampler = newSampler(config)
+	}
+}
+
+// allow решает, пропустить ли запись через выборку. Счетчики сбрасываются
+// целиком в начале каждого интервала, поэтому память не растет бесконечно.
+func (s *sampler) allow(entry *Entry, now time.Time) bool {
+	if s.config.Initial == 0 && s.config.Thereafter == 0 {
+		return true
+	}
+	if now.Sub(s.windowStart) >= s.config.Interval || now.Before(s.windowStart) {
+		s.windowStart = now
+		clear(s.counts)
+	}
+
+	key := sampleKey{level: entry.Level, message: entry.Message}
+	s.counts[key]++
+	n := s.counts[key]
+	if n <= s.config.Initial {
+		return true
+	}
+	if s.config.Thereafter > 0 && (n-s.config.Initial)%s.config.Thereafter == 0 {
+		return true
+	}
+	s.sampled++
+	return false
+}
+
+// repeat сообщает, совпадает ли запись с последней записанной.
+// Поля сравниваются по их текстовому представлению.
+func (s *sampler) repeat(key string) bool {
+	if !s.config.CollapseDuplicates || s.last == nil || key != s.lastKey {
+		return false
+	}
+	s.repeats++
+	s.collapsed++
+	return true
+}
+
+// remember запоминает записанную запись для сравнения со следующими
+func (s *sampler) remember(entry *Entry, key string) {
+	if s.config.CollapseDuplicates {
+		s.last = entry
+		s.lastKey = key
+	}
+}
+
+// summary возвращает запись о числе повторов последнего сообщения
+// и обнуляет их счетчик. Если повторов не было, возвращает nil.
+func (s *sampler) summary(now time.Time) *Entry {
+	if s.repeats == 0 {
+		return nil
+	}
+	entry := &Entry{
+		Time:    now,
+		Level:   s.last.Level,
+		Prefix:  s.last.Prefix,
+		Message: fmt.Sprintf("число повторов предыдущего сообщения: %d", s.repeats),
+		Fields:  []Field{F("repeated", s.repeats)},
+	}
+	s.repeats = 0
+	return entry
+}
+
+// repeatKey текстовое представление записи для поиска повторов
+func (sl *SmartLogger) repeatKey(entry *Entry) string {
+	if !sl.sampler.config.CollapseDuplicates {
+		return ""
+	}
+	return fmt.Sprint(entry.Level, entry.Prefix, entry.Message, entry.Fields)
+}
+
+// sample применяет к записи схлопывание повторов и выборку.
+// Возвращает false, если запись писать не нужно. Вызывается под мьютексом.
+func (sl *SmartLogger) sample(entry *Entry) (bool, string) {
+	key := sl.repeatKey(entry)
+	if sl.sampler.repeat(key) {
+		return false, key
+	}
+	sl.flushRepeats()
+	return sl.sampler.allow(entry, sl.now()), key
+}
+
+// flushRepeats выводит накопленную строку о повторах, если она есть.
+// Вызывается под мьютексом.
+func (sl *SmartLogger) flushRepeats() {
+	if sl.sampler == nil {
+		return
+	}
+	if entry := sl.sampler.summary(sl.now()); entry != nil {
+		sl.deliver(entry)
+		sl.countEntry(entry)
+	}
+}
+
+// GetSampledCount возвращает число записей, отброшенных выборкой
+func (sl *SmartLogger) GetSampledCount() int {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	if sl.sampler == nil {
+		return 0
+	}
+	return sl.sampler.sampled
+}
+
+// GetCollapsedCount возвращает число записей, схлопнутых как повторы
+func (sl *SmartLogger) GetCollapsedCount() int {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	if sl.sampler == nil {
+		return 0
+	}
+	return sl.sampler.collapsed
+}
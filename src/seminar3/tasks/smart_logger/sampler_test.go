@@ -0,0 +1,107 @@
+package smartlogger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplingFirstThenEveryNth(t *testing.T) {
+	logger, buf := newTestLogger("", WithSampling(SamplingConfig{Initial: 2, Thereafter: 3}))
+
+	for i := 1; i <= 10; i++ {
+		logger.Info("disk full")
+	}
+
+	// пропускаются 1, 2, затем 5 и 8
+	assert.Equal(t, 4, strings.Count(buf.String(), "disk full"))
+	assert.Equal(t, 4, logger.GetLogCount())
+	assert.Equal(t, 6, logger.GetSampledCount())
+}
+
+func TestSamplingPerKey(t *testing.T) {
+	logger, buf := newTestLogger("", WithSampling(SamplingConfig{Initial: 1}))
+
+	logger.Info("a")
+	logger.Info("a")
+	logger.Warn("a")
+	logger.Info("b")
+
+	assert.Equal(t, 3, strings.Count(buf.String(), "\n"))
+	assert.Equal(t, 1, logger.GetSampledCount())
+}
+
+func TestSamplingIntervalReset(t *testing.T) {
+	clock := &fakeClock{t: testTime}
+	logger, buf := newTestLogger("", WithClock(clock.Now), WithSampling(SamplingConfig{Initial: 1, Interval: time.Minute}))
+
+	logger.Info("tick")
+	logger.Info("tick")
+	clock.Advance(time.Minute)
+	logger.Info("tick")
+
+	assert.Equal(t, 2, strings.Count(buf.String(), "tick"))
+	assert.Equal(t, 1, logger.GetSampledCount())
+}
+
+func TestCollapseDuplicates(t *testing.T) {
+	logger, buf := newTestLogger("", WithSampling(SamplingConfig{Initial: 100, CollapseDuplicates: true}))
+
+	logger.Info("retry")
+	logger.Info("retry")
+	logger.Info("retry")
+	logger.Info("done")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "retry")
+	assert.Contains(t, lines[1], "число повторов предыдущего сообщения: 2")
+	assert.Contains(t, lines[1], "repeated=2")
+	assert.Contains(t, lines[2], "done")
+	assert.Equal(t, 2, logger.GetCollapsedCount())
+	assert.Equal(t, 3, logger.GetLogCount())
+}
+
+func TestCollapseComparesFields(t *testing.T) {
+	logger, buf := newTestLogger("", WithSampling(SamplingConfig{Initial: 100, CollapseDuplicates: true}))
+
+	logger.Infow("retry", "attempt", 1)
+	logger.Infow("retry", "attempt", 2)
+	logger.With("city", "Moscow").Infow("retry", "attempt", 2)
+
+	assert.Equal(t, 3, strings.Count(buf.String(), "retry"))
+	assert.Equal(t, 0, logger.GetCollapsedCount())
+}
+
+func TestCollapseFlushedOnSync(t *testing.T) {
+	logger, buf := newTestLogger("", WithSampling(SamplingConfig{Initial: 100, CollapseDuplicates: true}))
+
+	logger.Error("timeout")
+	logger.Error("timeout")
+	assert.NotContains(t, buf.String(), "повторов")
+
+	require.NoError(t, logger.Sync())
+	assert.Contains(t, buf.String(), "[ERROR]: число повторов предыдущего сообщения: 1")
+}
+
+func TestCollapseWithoutSampling(t *testing.T) {
+	logger, buf := newTestLogger("", WithSampling(SamplingConfig{CollapseDuplicates: true}))
+
+	logger.Info("retry")
+	logger.Info("retry")
+	logger.Info("done")
+	logger.Info("done")
+	require.NoError(t, logger.Sync())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4, "без Initial и Thereafter отличающиеся записи не отбрасываются")
+	assert.Contains(t, lines[0], "retry")
+	assert.Contains(t, lines[1], "число повторов предыдущего сообщения: 1")
+	assert.Contains(t, lines[2], "done")
+	assert.Contains(t, lines[3], "число повторов предыдущего сообщения: 1")
+	assert.Equal(t, 0, logger.GetSampledCount())
+	assert.Equal(t, 2, logger.GetCollapsedCount())
+}
@@ -0,0 +1,218 @@
+package smartlogger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Sink дополнительный приемник записей логгера со своим минимальным уровнем.
+// Общий уровень логгера проверяется раньше: чтобы приемник получал Debug,
+// уровень логгера тоже должен быть не выше Debug.
+type Sink interface {
+	Enabled(level Level) bool
+	WriteEntry(entry *Entry) error
+}
+
+// WriterSink кодирует записи своим Encoder и пишет их в io.Writer.
+// Медленный вывод стоит обернуть в AsyncWriter, чтобы он не задерживал остальные приемники.
+type WriterSink struct {
+	mu      sync.Mutex
+	w       io.Writer
+	level   Level
+	encoder Encoder
+	isColor bool
+	buf     bytes.Buffer
+}
+
+// NewWriterSink создает приемник. Если encoder равен nil, используется TextEncoder.
+func NewWriterSink(w io.Writer, level Level, encoder Encoder) *WriterSink {
+	if encoder == nil {
+		encoder = TextEncoder{}
+	}
+	return &WriterSink{w: w, level: level, encoder: encoder}
+}
+
+// EnableColor включает цвета безусловно, см. также SetColorMode
+func (s *WriterSink) EnableColor() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.isColor = true
+}
+
+func (s *WriterSink) Enabled(level Level) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return level >= s.level
+}
+
+func (s *WriterSink) WriteEntry(entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buf.Reset()
+	if err := s.encoder.Encode(&s.buf, entry, s.isColor); err != nil {
+		return err
+	}
+	_, err := s.w.Write(s.buf.Bytes())
+	return err
+}
+
+func (s *WriterSink) Flush(timeout time.Duration) error {
+	if flusher, ok := s.w.(interface{ Flush(time.Duration) error }); ok {
+		return flusher.Flush(timeout)
+	}
+	return nil
+}
+
+func (s *WriterSink) Sync() error {
+	if syncer, ok := s.w.(interface{ Sync() error }); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
+
+func (s *WriterSink) Close() error {
+	return closeOutput(s.w)
+}
+
+// Размер очереди приемника, создаваемого NewWebhookSink
+const webhookQueueSize = 256
+
+// NewWebhookSink создает приемник, отправляющий записи уровня level и выше
+// в url в формате JSON. Запросы выполняются из очереди AsyncWriter, поэтому
+// медленный или зависший вебхук не задерживает основной вывод и остальные
+// приемники; при переполнении очереди новые записи отбрасываются.
+// Ошибки отправки передаются в onError, а если он nil — пишутся в os.Stderr.
+func NewWebhookSink(url string, level Level, onError func(error)) *WriterSink {
+	if onError == nil {
+		onError = func(err error) {
+			fmt.Fprintf(os.Stderr, "smartlogger: ошибка приемника вебхука: %v\n", err)
+		}
+	}
+	webhook := NewAsyncWriter(NewWebhookWriter(url), AsyncConfig{
+		QueueSize: webhookQueueSize,
+		Policy:    DropNewest,
+		OnError:   onError,
+	})
+	return NewWriterSink(webhook, level, JSONEncoder{})
+}
+
+// WebhookWriter отправляет каждую запись отдельным POST-запросом.
+// Запрос выполняется синхронно, поэтому напрямую его используют только
+// внутри AsyncWriter, см. NewWebhookSink.
+type WebhookWriter struct {
+	client      *http.Client
+	url         string
+	contentType string
+}
+
+func NewWebhookWriter(url string) *WebhookWriter {
+	return &WebhookWriter{
+		client:      &http.Client{Timeout: 10 * time.Second},
+		url:         url,
+		contentType: "application/json",
+	}
+}
+
+func (w *WebhookWriter) Write(p []byte) (int, error) {
+	resp, err := w.client.Post(w.url, w.contentType, bytes.NewReader(p))
+	if err != nil {
+		return 0, fmt.Errorf("ошибка отправки вебхука: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("вебхук вернул ошибку: %s", resp.Status)
+	}
+	return len(p), nil
+}
+
+// WithSink добавляет приемник записей
+func WithSink(sink Sink) Option {
+	return func(sl *SmartLogger) {
+		sl.sinks = append(sl.sinks, sink)
+	}
+}
+
+// WithSinkErrorHandler задает обработчик ошибок приемников.
+// По умолчанию ошибки пишутся в os.Stderr. Обработчик вызывается после
+// освобождения мьютекса, поэтому может сообщать об ошибке через тот же логгер.
+// Ошибки, возникшие во время работы обработчика, пишутся в os.Stderr,
+// чтобы отказавший приемник не зациклил его.
+func WithSinkErrorHandler(fn func(sink Sink, err error)) Option {
+	return func(sl *SmartLogger) {
+		sl.onSinkError = fn
+	}
+}
+
+// AddSink добавляет приемник к работающему логгеру
+func (sl *SmartLogger) AddSink(sink Sink) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.sinks = append(sl.sinks, sink)
+}
+
+// writeSinks передает запись всем приемникам с подходящим уровнем.
+// Ошибка одного приемника не мешает остальным. Вызывается под мьютексом.
+func (sl *SmartLogger) writeSinks(entry *Entry) {
+	for _, sink := range sl.sinks {
+		if !sink.Enabled(entry.Level) {
+			continue
+		}
+		if err := sink.WriteEntry(entry); err != nil {
+			sl.sinkErrors = append(sl.sinkErrors, sinkError{sink, err})
+		}
+	}
+}
+
+// sinkError ошибка приемника, ожидающая передачи обработчику
+type sinkError struct {
+	sink Sink
+	err  error
+}
+
+// unlock освобождает мьютекс и передает накопленные ошибки приемников
+// обработчику. Используется вместо mu.Unlock там, где пишутся записи.
+func (sl *SmartLogger) unlock() {
+	errs := sl.sinkErrors
+	sl.sinkErrors = nil
+	nested := sl.reportingErrors
+	if len(errs) > 0 && !nested {
+		sl.reportingErrors = true
+	}
+	sl.mu.Unlock()
+
+	if len(errs) == 0 {
+		return
+	}
+	for _, e := range errs {
+		if nested || sl.onSinkError == nil {
+			fmt.Fprintf(os.Stderr, "smartlogger: ошибка приемника %T: %v\n", e.sink, e.err)
+			continue
+		}
+		sl.onSinkError(e.sink, e.err)
+	}
+	if !nested {
+		sl.mu.Lock()
+		sl.reportingErrors = false
+		sl.mu.Unlock()
+	}
+}
+
+// eachWriter вызывает fn для output и всех приемников, объединяя ошибки
+func (sl *SmartLogger) eachWriter(fn func(w interface{}) error) error {
+	var errs []error
+	if sl.output != nil {
+		errs = append(errs, fn(sl.output))
+	}
+	for _, sink := range sl.sinks {
+		errs = append(errs, fn(sink))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,213 @@
+package smartlogger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultipleSinks(t *testing.T) {
+	var terminal, file, alerts strings.Builder
+
+	terminalSink := NewWriterSink(&terminal, Info, TextEncoder{})
+	terminalSink.EnableColor()
+
+	logger := NewSmartLogger(nil, "APP",
+		WithClock(testClock),
+		WithSink(terminalSink),
+		WithSink(NewWriterSink(&file, Debug, JSONEncoder{})),
+		WithSink(NewWriterSink(&alerts, Error, LogfmtEncoder{})),
+	)
+	logger.SetLevel(Debug)
+
+	logger.Debug("debug")
+	logger.Infow("info", "k", 1)
+	logger.Error("error")
+
+	assert.Equal(t, "2024-03-15 10:30:45 APP \033[32m[INFO]\033[0m: info k=1\n"+
+		"2024-03-15 10:30:45 APP \033[31m[ERROR]\033[0m: error\n", terminal.String())
+
+	lines := parseJSONLines(t, []byte(file.String()))
+	require.Len(t, lines, 3)
+	assert.Equal(t, "DEBUG", lines[0]["level"])
+	assert.Equal(t, 1.0, lines[1]["k"])
+
+	assert.Equal(t, "time="+testTime.Format(time.RFC3339Nano)+" level=ERROR prefix=APP msg=error\n", alerts.String())
+	assert.Equal(t, 3, logger.GetLogCount(), "запись учитывается один раз")
+}
+
+func TestSinksWithPrimaryOutput(t *testing.T) {
+	logger, primary := newTestLogger("APP")
+	var extra strings.Builder
+	logger.AddSink(NewWriterSink(&extra, Warn, nil))
+
+	logger.Info("only primary")
+	logger.Warn("both")
+	fmt.Fprintln(logger, "через Write")
+
+	assert.Equal(t, 3, strings.Count(primary.String(), "\n"))
+	assert.Equal(t, "2024-03-15 10:30:45 APP [WARN]: both\n", extra.String())
+}
+
+func TestFailingSinkDoesNotAffectOthers(t *testing.T) {
+	var healthy strings.Builder
+	failing := NewWriterSink(failingWriter{}, Info, nil)
+
+	var mu sync.Mutex
+	var reported []error
+	logger := NewSmartLogger(nil, "APP",
+		WithSink(failing),
+		WithSink(NewWriterSink(&healthy, Info, nil)),
+		WithSinkErrorHandler(func(sink Sink, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Same(t, failing, sink)
+			reported = append(reported, err)
+		}),
+	)
+
+	logger.Info("first")
+	logger.Info("second")
+
+	assert.Equal(t, 2, strings.Count(healthy.String(), "\n"))
+	require.Len(t, reported, 2)
+	assert.EqualError(t, reported[0], "disk full")
+}
+
+func TestSinkErrorHandlerCanLogThroughSameLogger(t *testing.T) {
+	var logger *SmartLogger
+	var reported int
+	logger, buf := newTestLogger("APP",
+		WithSink(NewWriterSink(failingWriter{}, Info, nil)),
+		WithSinkErrorHandler(func(_ Sink, err error) {
+			reported++
+			// запись из обработчика снова упадет в том же приемнике,
+			// эта ошибка уходит в stderr, а не обратно в обработчик
+			logger.Warnw("приемник недоступен", "err", err)
+		}),
+	)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("first")
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("обработчик ошибок приемника заблокировал логгер")
+	}
+
+	assert.Equal(t, 1, reported)
+	assert.Equal(t, "2024-03-15 10:30:45 APP [INFO]: first\n"+
+		"2024-03-15 10:30:45 APP [WARN]: приемник недоступен err=\"disk full\"\n", buf.String())
+}
+
+func TestWebhookSink(t *testing.T) {
+	var mu sync.Mutex
+	var received []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		var m map[string]interface{}
+		assert.NoError(t, json.Unmarshal(body, &m))
+		mu.Lock()
+		received = append(received, m)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	logger := NewSmartLogger(nil, "APP", WithSink(NewWebhookSink(server.URL, Error, nil)))
+
+	logger.Warn("не отправляется")
+	logger.Errorw("отправляется", "code", 500)
+	require.NoError(t, logger.Flush(time.Second))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 1)
+	assert.Equal(t, "отправляется", received[0]["msg"])
+	assert.Equal(t, 500.0, received[0]["code"])
+}
+
+func TestSlowWebhookDoesNotBlockOtherSinks(t *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+	}))
+	defer server.Close()
+
+	var errs []error
+	var errMu sync.Mutex
+	webhook := NewWebhookSink(server.URL, Error, func(err error) {
+		errMu.Lock()
+		errs = append(errs, err)
+		errMu.Unlock()
+	})
+	var file strings.Builder
+	logger := NewSmartLogger(nil, "APP", WithClock(testClock),
+		WithSink(webhook),
+		WithSink(NewWriterSink(&file, Info, nil)),
+	)
+	defer logger.Close()
+	defer close(release)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		logger.Errorw("сбой", "n", i)
+	}
+	assert.Less(t, time.Since(start), time.Second, "вебхук не задерживает запись")
+	assert.Equal(t, 3, strings.Count(file.String(), "[ERROR]: сбой"))
+	assert.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, 10*time.Millisecond,
+		"первый запрос висит, остальные ждут в очереди")
+
+	errMu.Lock()
+	defer errMu.Unlock()
+	assert.Empty(t, errs)
+}
+
+func TestWebhookWriterErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := NewWebhookWriter(server.URL).Write([]byte("{}"))
+	assert.ErrorContains(t, err, "502")
+}
+
+func TestCloseClosesSinks(t *testing.T) {
+	primary := &closeRecorder{}
+	sinkOutput := &closeRecorder{}
+	logger := NewSmartLogger(primary, "APP", WithSink(NewWriterSink(sinkOutput, Info, nil)))
+
+	require.NoError(t, logger.Close())
+	assert.True(t, primary.closed)
+	assert.True(t, sinkOutput.closed)
+
+	failing := NewSmartLogger(nil, "APP", WithSink(NewWriterSink(&closeFailer{}, Info, nil)))
+	assert.True(t, errors.Is(failing.Close(), errCloseFailed))
+}
+
+var errCloseFailed = errors.New("close failed")
+
+type closeFailer struct {
+	strings.Builder
+}
+
+func (*closeFailer) Close() error {
+	return errCloseFailed
+}
@@ -0,0 +1,190 @@
+package smartlogger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler реализует slog.Handler поверх SmartLogger.
+// slog.New(NewSlogHandler(logger)) использует префикс, фильтрацию по уровню,
+// кодировщик, цвета и счетчики логгера.
+type SlogHandler struct {
+	logger *SmartLogger
+	goas   []groupOrAttrs
+}
+
+// groupOrAttrs один вызов WithGroup или WithAttrs в цепочке обработчика
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+func NewSlogHandler(logger *SmartLogger) *SlogHandler {
+	return &SlogHandler{logger: logger}
+}
+
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.enabled(fromSlogLevel(level))
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	level := fromSlogLevel(r.Level)
+
+	fields := make([]Field, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, a)
+		return true
+	})
+
+	// Группы применяются изнутри наружу: атрибуты записи оказываются
+	// в самой вложенной группе, пустые группы не выводятся.
+	for i := len(h.goas) - 1; i >= 0; i-- {
+		goa := h.goas[i]
+		if goa.group != "" {
+			if len(fields) > 0 {
+				fields = []Field{Group(goa.group, fields...)}
+			}
+			continue
+		}
+
+		var attrs []Field
+		for _, a := range goa.attrs {
+			attrs = appendAttr(attrs, a)
+		}
+		fields = append(attrs, fields...)
+	}
+
+	entry := &Entry{Time: r.Time, Level: level, Message: r.Message, Fields: fields, ctx: ctx}
+	h.logger.captureFromPC(entry, r.PC)
+	return h.logger.writeEntry(entry)
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(groupOrAttrs{attrs: attrs})
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(groupOrAttrs{group: name})
+}
+
+func (h *SlogHandler) withGroupOrAttrs(goa groupOrAttrs) *SlogHandler {
+	goas := make([]groupOrAttrs, 0, len(h.goas)+1)
+	goas = append(goas, h.goas...)
+	return &SlogHandler{logger: h.logger, goas: append(goas, goa)}
+}
+
+// WithHandler перенаправляет записи в slog.Handler вместо output.
+// Уровень, префикс, поля и счетчики SmartLogger продолжают работать.
+func WithHandler(handler slog.Handler) Option {
+	return func(sl *SmartLogger) {
+		sl.handler = handler
+	}
+}
+
+// forward передает запись в slog.Handler. Префикс становится атрибутом prefix,
+// контекст записи (WithContext или Handle) доходит до обработчика.
+func (sl *SmartLogger) forward(entry *Entry) error {
+	ctx := entry.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	level := toSlogLevel(entry.Level)
+	if !sl.handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	record := slog.NewRecord(entry.Time, level, entry.Message, entry.pc)
+	if entry.Prefix != "" {
+		record.AddAttrs(slog.String(PrefixKey, entry.Prefix))
+	}
+	if entry.Stack != "" {
+		record.AddAttrs(slog.String(StackKey, entry.Stack))
+	}
+	for _, field := range entry.Fields {
+		record.AddAttrs(fieldToAttr(field))
+	}
+	return sl.handler.Handle(ctx, record)
+}
+
+// appendAttr превращает атрибут slog в поле, отбрасывая пустые атрибуты и группы
+func appendAttr(fields []Field, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		return append(fields, Field{Key: a.Key, Value: a.Value.Any()})
+	}
+
+	var group []Field
+	for _, ga := range a.Value.Group() {
+		group = appendAttr(group, ga)
+	}
+	if len(group) == 0 {
+		return fields
+	}
+	if a.Key == "" {
+		return append(fields, group...)
+	}
+	return append(fields, Group(a.Key, group...))
+}
+
+func fieldToAttr(field Field) slog.Attr {
+	group, ok := field.Value.([]Field)
+	if !ok {
+		return slog.Any(field.Key, field.Value)
+	}
+
+	attrs := make([]slog.Attr, 0, len(group))
+	for _, f := range group {
+		attrs = append(attrs, fieldToAttr(f))
+	}
+	return slog.Attr{Key: field.Key, Value: slog.GroupValue(attrs...)}
+}
+
+// Соответствие уровней SmartLogger уровням slog.
+// Trace и Fatal лежат на шаг (4) ниже Debug и выше Error соответственно.
+const (
+	slogLevelTrace = slog.LevelDebug - 4
+	slogLevelFatal = slog.LevelError + 4
+)
+
+func toSlogLevel(level Level) slog.Level {
+	switch level {
+	case Trace:
+		return slogLevelTrace
+	case Debug:
+		return slog.LevelDebug
+	case Warn:
+		return slog.LevelWarn
+	case Error:
+		return slog.LevelError
+	case Fatal:
+		return slogLevelFatal
+	}
+	return slog.LevelInfo
+}
+
+// fromSlogLevel сопоставляет уровень slog ближайшему снизу уровню SmartLogger
+func fromSlogLevel(level slog.Level) Level {
+	switch {
+	case level >= slogLevelFatal:
+		return Fatal
+	case level >= slog.LevelError:
+		return Error
+	case level >= slog.LevelWarn:
+		return Warn
+	case level >= slog.LevelInfo:
+		return Info
+	case level >= slog.LevelDebug:
+		return Debug
+	}
+	return Trace
+}
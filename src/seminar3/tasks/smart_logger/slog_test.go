@@ -0,0 +1,111 @@
+package smartlogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseJSONLines(t *testing.T, data []byte) []map[string]any {
+	t.Helper()
+	var results []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(line, &m), "строка: %s", line)
+		results = append(results, m)
+	}
+	return results
+}
+
+func TestSlogHandlerConformance(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSmartLogger(&buf, "", WithEncoder(JSONEncoder{}))
+
+	err := slogtest.TestHandler(NewSlogHandler(logger), func() []map[string]any {
+		return parseJSONLines(t, buf.Bytes())
+	})
+	require.NoError(t, err)
+}
+
+func TestSlogHandlerUsesLogger(t *testing.T) {
+	logger, buf := newTestLogger("SLOG")
+	logger.EnableColor()
+	logger.SetLevel(Warn)
+
+	slogger := slog.New(NewSlogHandler(logger)).With("service", "weather").WithGroup("req")
+	slogger.Info("filtered")
+	slogger.Warn("slow request", "city", "Moscow", slog.Group("timing", "ms", 1500))
+
+	// Время записи задает slog, поэтому сравнивается строка после метки времени
+	_, line, found := strings.Cut(buf.String(), " ")
+	require.True(t, found)
+	_, line, _ = strings.Cut(line, " ")
+	assert.Equal(t, "SLOG \033[33m[WARN]\033[0m: slow request service=weather req.city=Moscow req.timing.ms=1500\n", line)
+	assert.Equal(t, 1, logger.GetLogCount())
+}
+
+func TestSlogHandlerEnabled(t *testing.T) {
+	logger, _ := newTestLogger("SLOG")
+	handler := NewSlogHandler(logger)
+	ctx := context.Background()
+
+	assert.False(t, handler.Enabled(ctx, slog.LevelDebug))
+	assert.True(t, handler.Enabled(ctx, slog.LevelInfo))
+
+	logger.SetLevel(Error)
+	assert.False(t, handler.Enabled(ctx, slog.LevelWarn))
+	assert.True(t, handler.Enabled(ctx, slog.LevelError+2))
+}
+
+func TestForwardToSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	target := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})
+	logger := NewSmartLogger(nil, "APP", WithClock(testClock), WithHandler(target))
+
+	logger.Info("ниже уровня обработчика")
+	logger.With("request_id", "abc").Errorw("failed", Group("db", F("table", "users")))
+
+	results := parseJSONLines(t, buf.Bytes())
+	require.Len(t, results, 1)
+	assert.Equal(t, "ERROR", results[0]["level"])
+	assert.Equal(t, "failed", results[0]["msg"])
+	assert.Equal(t, "APP", results[0]["prefix"])
+	assert.Equal(t, "abc", results[0]["request_id"])
+	assert.Equal(t, map[string]any{"table": "users"}, results[0]["db"])
+	assert.True(t, strings.HasPrefix(results[0]["time"].(string), "2024-03-15T10:30:45"))
+	assert.Equal(t, 2, logger.GetLogCount())
+}
+
+// contextHandler запоминает значение requestIDKey из контекста каждой записи
+type contextHandler struct {
+	slog.Handler
+	seen []any
+}
+
+func (h *contextHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *contextHandler) Handle(ctx context.Context, _ slog.Record) error {
+	h.seen = append(h.seen, ctx.Value(requestIDKey{}))
+	return nil
+}
+
+func TestForwardPassesContext(t *testing.T) {
+	target := &contextHandler{}
+	logger := NewSmartLogger(nil, "APP", WithHandler(target))
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-7")
+
+	logger.WithContext(ctx).Info("с контекстом")
+	logger.Info("без контекста")
+
+	assert.Equal(t, []any{"req-7", nil}, target.seen)
+}
@@ -1,193 +1,374 @@
-package main
+// Package smartlogger простой логгер с уровнями, префиксом и цветным выводом.
+// SmartLogger реализует io.Writer, fmt.Stringer и fmt.GoStringer.
+package smartlogger
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
-	"strings"
+	"sync"
 	"time"
 )
 
-type Level int
+// SmartLogger безопасен для одновременного использования из нескольких горутин:
+// каждая строка записывается в output одним вызовом Write под мьютексом.
+// Дочерние логгеры, созданные через With, разделяют состояние с родителем.
+type SmartLogger struct {
+	*loggerCore
+	fields     []Field
+	callerSkip int
+	ctx        context.Context
+}
 
-const (
-	Info Level = iota
-	Warn
-	Error
-)
+// loggerCore общее состояние логгера и всех его дочерних логгеров
+type loggerCore struct {
+	mu              sync.Mutex
+	output          io.Writer
+	prefix          string
+	level           *AtomicLevel // читается без мьютекса
+	stats           statsCounter
+	isColor         bool
+	colorMode       ColorMode
+	now             func() time.Time
+	encoder         Encoder
+	handler         slog.Handler
+	exit            func(code int)
+	async           *AsyncConfig
+	sinks           []Sink
+	onSinkError     func(sink Sink, err error)
+	sinkErrors      []sinkError // ошибки приемников, передаются обработчику в unlock
+	reportingErrors bool        // обработчик ошибок приемников сейчас работает
+	sampler         *sampler
+	caller          bool
+	stack           bool
+	stackLevel      Level
+	detectLevel     bool
+	partial         []byte // незавершенная строка, пришедшая через Write
+	hooks           []Hook
+	filtered        int // отброшено хуками
+	buf             bytes.Buffer
+}
+
+// Option настраивает SmartLogger при создании
+type Option func(*SmartLogger)
 
-func (l Level) String() string {
-	switch l {
-	case Info:
-		return "INFO"
-	case Warn:
-		return "WARN"
-	case Error:
-		return "ERROR"
-	default:
-		return "UNKNOWN"
+// WithEncoder задает формат записей, по умолчанию TextEncoder
+func WithEncoder(encoder Encoder) Option {
+	return func(sl *SmartLogger) {
+		sl.encoder = encoder
 	}
 }
 
-type SmartLogger struct {
-	output   io.Writer
-	prefix   string
-	level    Level
-	logCount int
-	isColor  bool
+// WithExitFunc задает функцию завершения программы для Fatal, по умолчанию os.Exit
+func WithExitFunc(exit func(code int)) Option {
+	return func(sl *SmartLogger) {
+		sl.exit = exit
+	}
 }
 
-func NewSmartLogger(output io.Writer, prefix string) *SmartLogger {
-	return &SmartLogger{
-		output:   output,
-		prefix:   prefix,
-		level:    Info,
-		logCount: 0,
-		isColor:  false,
+// WithClock задает источник времени для меток в логах
+func WithClock(now func() time.Time) Option {
+	return func(sl *SmartLogger) {
+		sl.now = now
 	}
 }
 
+func NewSmartLogger(output io.Writer, prefix string, options ...Option) *SmartLogger {
+	sl := &SmartLogger{loggerCore: &loggerCore{
+		output:  output,
+		prefix:  prefix,
+		level:   NewAtomicLevel(Info),
+		stats:   newStatsCounter(defaultStatsWindow),
+		isColor: false,
+		now:     time.Now,
+		encoder: TextEncoder{},
+		exit:    os.Exit,
+	}}
+
+	for _, option := range options {
+		option(sl)
+	}
+	sl.isColor = useColor(sl.colorMode, sl.output)
+
+	if sl.async != nil && sl.output != nil {
+		sl.output = NewAsyncWriter(sl.output, *sl.async)
+	}
+
+	return sl
+}
+
+// SetLevel меняет уровень; безопасно вызывать во время записи из других горутин
 func (sl *SmartLogger) SetLevel(level Level) {
-	sl.level = level
+	sl.level.SetLevel(level)
 }
 
+// EnableColor включает цвета безусловно, как SetColorMode(ColorAlways).
+// Чтобы цвета не попадали в файлы и логи CI, используйте ColorAuto.
 func (sl *SmartLogger) EnableColor() {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
 	sl.isColor = true
 }
 
-func (sl *SmartLogger) Write(p []byte) (n int, err error) {
-	message := strings.TrimSpace(string(p))
-	return sl.output.Write([]byte(sl.formatLog(Info, message)))
-}
-
 func (sl *SmartLogger) String() string {
-	return fmt.Sprintf("SmartLogger{prefix: '%s', level: %s, logs: %d}",
-		sl.prefix, sl.level, sl.logCount)
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	stats := sl.snapshot()
+	return fmt.Sprintf("SmartLogger{prefix: '%s', level: %s, logs: %d, errors: %d, bytes: %d, rate: %.2f/s}",
+		sl.prefix, sl.level.Level(), stats.Total, stats.Errors(), stats.Bytes, stats.Rate)
 }
 
 func (sl *SmartLogger) GoString() string {
-	return fmt.Sprintf("SmartLogger{prefix: %q, level: %v, logCount: %d, isColor: %t}",
-		sl.prefix, sl.level, sl.logCount, sl.isColor)
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	return fmt.Sprintf("SmartLogger{prefix: %q, level: %v, logCount: %d, isColor: %t, stats: %q}",
+		sl.prefix, sl.level.Level(), sl.stats.total, sl.isColor, sl.snapshot().String())
+}
+
+func (sl *SmartLogger) Trace(format string, args ...interface{}) {
+	if sl.enabled(Trace) {
+		sl.log(Trace, format, args...)
+	}
 }
 
+func (sl *SmartLogger) Debug(format string, args ...interface{}) {
+	if sl.enabled(Debug) {
+		sl.log(Debug, format, args...)
+	}
+}
+
+// Info пишет сообщение уровня Info в стиле fmt.Printf. Для пар ключ-значение
+// используйте Infow: Info("done", "city", city) выведет "%!(EXTRA ...)".
+// Так же устроены Trace, Debug, Warn, Error и Fatal.
 func (sl *SmartLogger) Info(format string, args ...interface{}) {
-	if sl.level <= Info {
+	if sl.enabled(Info) {
 		sl.log(Info, format, args...)
 	}
 }
 
 func (sl *SmartLogger) Warn(format string, args ...interface{}) {
-	if sl.level <= Warn {
+	if sl.enabled(Warn) {
 		sl.log(Warn, format, args...)
 	}
 }
 
 func (sl *SmartLogger) Error(format string, args ...interface{}) {
-	if sl.level <= Error {
+	if sl.enabled(Error) {
 		sl.log(Error, format, args...)
 	}
 }
 
-// Вспомогательные методы
-func (sl *SmartLogger) log(level Level, format string, args ...interface{}) {
-	message := fmt.Sprintf(format, args...)
-	formatted := sl.formatLog(level, message)
-
-	if sl.output != nil {
-		sl.output.Write([]byte(formatted))
+// Fatal пишет сообщение уровня Fatal, сбрасывает вывод и завершает программу
+// с кодом 1. Функцию завершения можно заменить через WithExitFunc.
+func (sl *SmartLogger) Fatal(format string, args ...interface{}) {
+	if sl.enabled(Fatal) {
+		sl.log(Fatal, format, args...)
 	}
-	sl.logCount++
+	sl.fatalExit()
 }
 
-func (sl *SmartLogger) formatLog(level Level, message string) string {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
+func (sl *SmartLogger) Tracew(msg string, keysAndValues ...interface{}) {
+	if sl.enabled(Trace) {
+		sl.logw(Trace, msg, keysAndValues)
+	}
+}
 
-	var levelStr string
-	if sl.isColor {
-		levelStr = sl.colorizeLevel(level)
-	} else {
-		levelStr = fmt.Sprintf("[%s]", level)
+func (sl *SmartLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	if sl.enabled(Debug) {
+		sl.logw(Debug, msg, keysAndValues)
 	}
+}
 
-	return fmt.Sprintf("%s %s %s: %s\n", timestamp, sl.prefix, levelStr, message)
+// Infow пишет сообщение уровня Info со структурированными полями:
+// Infow("request done", "city", city, "latency", d).
+// Отдельные методы *w нужны, чтобы Info и остальные сохранили printf-семантику.
+func (sl *SmartLogger) Infow(msg string, keysAndValues ...interface{}) {
+	if sl.enabled(Info) {
+		sl.logw(Info, msg, keysAndValues)
+	}
 }
 
-func (sl *SmartLogger) colorizeLevel(level Level) string {
-	colorCode := "37"
-	switch level {
-	case Info:
-		colorCode = "32"
-	case Warn:
-		colorCode = "33"
-	case Error:
-		colorCode = "31"
+func (sl *SmartLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	if sl.enabled(Warn) {
+		sl.logw(Warn, msg, keysAndValues)
 	}
+}
 
-	return fmt.Sprintf("\033[%sm[%s]\033[0m", colorCode, level)
+func (sl *SmartLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	if sl.enabled(Error) {
+		sl.logw(Error, msg, keysAndValues)
+	}
 }
 
-func (sl *SmartLogger) Close() error {
-	if closer, ok := sl.output.(io.Closer); ok && sl.output != os.Stdout {
-		return closer.Close()
+func (sl *SmartLogger) Fatalw(msg string, keysAndValues ...interface{}) {
+	if sl.enabled(Fatal) {
+		sl.logw(Fatal, msg, keysAndValues)
 	}
-	return nil
+	sl.fatalExit()
 }
 
-func (sl *SmartLogger) Reset() {
-	sl.logCount = 0
+// With возвращает дочерний логгер, добавляющий поля к каждой записи.
+// Дочерний логгер разделяет с родителем вывод, уровень и счетчики.
+func (sl *SmartLogger) With(keysAndValues ...interface{}) *SmartLogger {
+	fields := make([]Field, 0, len(sl.fields)+len(keysAndValues)/2)
+	fields = append(fields, sl.fields...)
+	fields = append(fields, fieldsFromArgs(keysAndValues)...)
+	child := sl.clone()
+	child.fields = fields
+	return child
 }
 
-func (sl *SmartLogger) GetLogCount() int {
-	return sl.logCount
+// clone возвращает дочерний логгер с теми же полями и настройками
+func (sl *SmartLogger) clone() *SmartLogger {
+	child := *sl
+	return &child
 }
 
-func main() {
-	fmt.Println("=== Демонстрация SmartLogger ===")
+// Вспомогательные методы
+func (sl *SmartLogger) enabled(level Level) bool {
+	return sl.level.Enabled(level)
+}
 
-	// 1. Создаем логгер для консоли
-	consoleLogger := NewSmartLogger(os.Stdout, "APP")
-	consoleLogger.EnableColor()
+// log форматирует сообщение вне блокировки, чтобы методы String() аргументов
+// могли сами писать в логгер, а затем атомарно записывает строку.
+func (sl *SmartLogger) log(level Level, format string, args ...interface{}) {
+	sl.write(level, fmt.Sprintf(format, args...), nil)
+}
 
-	// Используем как обычный логгер
-	consoleLogger.Info("Приложение запущено")
-	consoleLogger.Warn("Нагрузка выше обычной: %.1f%%", 85.5)
-	consoleLogger.Error("Ошибка подключения к БД")
+func (sl *SmartLogger) logw(level Level, msg string, keysAndValues []interface{}) {
+	sl.write(level, msg, fieldsFromArgs(keysAndValues))
+}
 
-	// 2. Используем как io.Writer
-	fmt.Println("\n=== Использование как io.Writer ===")
-	fmt.Fprintf(consoleLogger, "Это сообщение через fmt.Fprintf")
+func (sl *SmartLogger) write(level Level, message string, fields []Field) {
+	entry := &Entry{Time: sl.now(), Level: level, Message: message, Fields: fields, ctx: sl.ctx}
+	sl.capture(entry, writeCallerDepth)
+	sl.writeEntry(entry)
+}
 
-	// 3. Демонстрация интерфейсов Stringer и GoStringer
-	fmt.Println("\n=== Stringer и GoStringer ===")
-	fmt.Println("String():", consoleLogger.String())
-	fmt.Printf("GoString(): %#v\n", consoleLogger)
+// writeEntry атомарно записывает запись и учитывает ее в счетчике.
+// Если включена выборка, запись может быть отброшена или схлопнута.
+func (sl *SmartLogger) writeEntry(entry *Entry) error {
+	sl.mu.Lock()
+	defer sl.unlock()
+	return sl.writeLocked(entry)
+}
 
-	// 4. Логгер в буфер (удовлетворяет io.Writer)
-	fmt.Println("\n=== Логгер в буфер ===")
-	var buf strings.Builder
-	bufferLogger := NewSmartLogger(&buf, "TEST")
-	bufferLogger.Info("Тестовое сообщение 1")
-	bufferLogger.Warn("Тестовое сообщение 2")
+// writeLocked тело writeEntry, вызывается под мьютексом
+func (sl *SmartLogger) writeLocked(entry *Entry) error {
+	sl.prepare(entry)
+	if !sl.runHooks(entry) {
+		sl.filtered++
+		return nil
+	}
 
-	fmt.Println("Логи в буфере:")
-	fmt.Print(buf.String())
-	fmt.Printf("Всего логов: %d\n", bufferLogger.GetLogCount())
+	var key string
+	if sl.sampler != nil {
+		var ok bool
+		if ok, key = sl.sample(entry); !ok {
+			return nil
+		}
+	}
 
-	// 5. Фильтрация по уровню
-	fmt.Println("\n=== Фильтрация по уровню ===")
-	filteredLogger := NewSmartLogger(os.Stdout, "FILTERED")
-	filteredLogger.SetLevel(Warn) // Только Warn и Error
+	err := sl.deliver(entry)
+	sl.countEntry(entry)
+	if sl.sampler != nil {
+		sl.sampler.remember(entry, key)
+	}
+	return err
+}
 
-	filteredLogger.Info("Это сообщение НЕ должно появиться") // Не появится
-	filteredLogger.Warn("А это должно появиться")            // Появится
-	filteredLogger.Error("И это тоже")                       // Появится
+// prepare дополняет запись префиксом и полями логгера
+func (sl *SmartLogger) prepare(entry *Entry) {
+	entry.Prefix = sl.prefix
+	if len(sl.fields) > 0 {
+		fields := make([]Field, 0, len(sl.fields)+len(entry.Fields))
+		fields = append(fields, sl.fields...)
+		entry.Fields = append(fields, entry.Fields...)
+	}
+}
 
-	// 6. Использование в функциях, принимающие io.Writer
-	fmt.Println("\n=== Использование с стандартными функциями ===")
-	writeToLogger(consoleLogger, "Сообщение через функцию")
+// deliver отправляет запись в основной вывод и во все приемники.
+// Вызывается под мьютексом.
+func (sl *SmartLogger) deliver(entry *Entry) error {
+	err := sl.writeOutput(entry)
+	sl.writeSinks(entry)
+	return err
 }
 
-// Функция, принимающая io.Writer - наш логгер подходит!
-func writeToLogger(w io.Writer, message string) {
-	fmt.Fprintf(w, "Пишем в io.Writer: %s", message)
+// writeOutput отправляет запись в slog.Handler или, если он не задан, в output
+func (sl *SmartLogger) writeOutput(entry *Entry) error {
+	if sl.handler != nil {
+		return sl.forward(entry)
+	}
+	if sl.output == nil {
+		return nil
+	}
+
+	formatted, err := sl.encode(entry)
+	if err != nil {
+		return err
+	}
+	n, err := sl.output.Write(formatted)
+	sl.stats.bytes += int64(n)
+	return err
+}
+
+// encode сериализует запись во внутренний буфер.
+// Вызывается под мьютексом; результат действителен до следующего вызова.
+func (sl *SmartLogger) encode(entry *Entry) ([]byte, error) {
+	sl.buf.Reset()
+	if err := sl.encoder.Encode(&sl.buf, entry, sl.isColor); err != nil {
+		return nil, err
+	}
+	return sl.buf.Bytes(), nil
+}
+
+// Sync сбрасывает буферы output и приемников, если они это поддерживают (например, *os.File)
+func (sl *SmartLogger) Sync() error {
+	sl.mu.Lock()
+	defer sl.unlock()
+	sl.flushPartial()
+	sl.flushRepeats()
+	return sl.eachWriter(func(w interface{}) error {
+		if syncer, ok := w.(interface{ Sync() error }); ok {
+			return syncer.Sync()
+		}
+		return nil
+	})
+}
+
+func (sl *SmartLogger) fatalExit() {
+	sl.Sync()
+	sl.exit(1)
+}
+
+// Close закрывает output и приемники, если это io.Closer. Стандартные потоки
+// os.Stdout и os.Stderr не закрываются.
+func (sl *SmartLogger) Close() error {
+	sl.mu.Lock()
+	defer sl.unlock()
+	sl.flushPartial()
+	sl.flushRepeats()
+	return sl.eachWriter(closeOutput)
+}
+
+// Reset обнуляет счетчики и статистику логгера
+func (sl *SmartLogger) Reset() {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.stats.reset()
+	sl.filtered = 0
+	if sl.sampler != nil {
+		sl.sampler.sampled = 0
+		sl.sampler.collapsed = 0
+	}
+}
+
+func (sl *SmartLogger) GetLogCount() int {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	return sl.stats.total
 }
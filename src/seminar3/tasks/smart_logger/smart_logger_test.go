@@ -0,0 +1,186 @@
+package smartlogger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2024, 3, 15, 10, 30, 45, 0, time.Local)
+
+func testClock() time.Time {
+	return testTime
+}
+
+// newTestLogger пишет в буфер с фиксированным временем testClock;
+// options применяются после часов и могут их заменить
+func newTestLogger(prefix string, options ...Option) (*SmartLogger, *strings.Builder) {
+	var buf strings.Builder
+	options = append([]Option{WithClock(testClock)}, options...)
+	return NewSmartLogger(&buf, prefix, options...), &buf
+}
+
+func TestLevelString(t *testing.T) {
+	assert.Equal(t, "TRACE", Trace.String())
+	assert.Equal(t, "DEBUG", Debug.String())
+	assert.Equal(t, "INFO", Info.String())
+	assert.Equal(t, "WARN", Warn.String())
+	assert.Equal(t, "ERROR", Error.String())
+	assert.Equal(t, "FATAL", Fatal.String())
+	assert.Equal(t, "UNKNOWN", Level(42).String())
+}
+
+func TestFormatting(t *testing.T) {
+	logger, buf := newTestLogger("APP")
+
+	logger.Info("Приложение запущено")
+	logger.Warn("Нагрузка: %.1f%%", 85.5)
+	logger.Error("Ошибка %d: %s", 500, "internal")
+
+	expected := "2024-03-15 10:30:45 APP [INFO]: Приложение запущено\n" +
+		"2024-03-15 10:30:45 APP [WARN]: Нагрузка: 85.5%\n" +
+		"2024-03-15 10:30:45 APP [ERROR]: Ошибка 500: internal\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestLevelFiltering(t *testing.T) {
+	tests := []struct {
+		level    Level
+		expected []string
+	}{
+		{Info, []string{"[INFO]", "[WARN]", "[ERROR]"}},
+		{Warn, []string{"[WARN]", "[ERROR]"}},
+		{Error, []string{"[ERROR]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			logger, buf := newTestLogger("TEST")
+			logger.SetLevel(tt.level)
+
+			logger.Info("info")
+			logger.Warn("warn")
+			logger.Error("error")
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			require.Len(t, lines, len(tt.expected))
+			for i, level := range tt.expected {
+				assert.Contains(t, lines[i], level)
+			}
+			assert.Equal(t, len(tt.expected), logger.GetLogCount())
+		})
+	}
+}
+
+func TestColorOutput(t *testing.T) {
+	logger, buf := newTestLogger("APP")
+	logger.EnableColor()
+
+	logger.Info("i")
+	logger.Warn("w")
+	logger.Error("e")
+
+	expected := "2024-03-15 10:30:45 APP \033[32m[INFO]\033[0m: i\n" +
+		"2024-03-15 10:30:45 APP \033[33m[WARN]\033[0m: w\n" +
+		"2024-03-15 10:30:45 APP \033[31m[ERROR]\033[0m: e\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWrite(t *testing.T) {
+	logger, buf := newTestLogger("IO")
+
+	input := "  сообщение через Write \n"
+	n, err := logger.Write([]byte(input))
+	require.NoError(t, err)
+	assert.Equal(t, len(input), n, "Write должен возвращать длину входных данных")
+
+	fmt.Fprintf(logger, "через %s\n", "Fprintf")
+
+	expected := "2024-03-15 10:30:45 IO [INFO]: сообщение через Write\n" +
+		"2024-03-15 10:30:45 IO [INFO]: через Fprintf\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriteError(t *testing.T) {
+	logger := NewSmartLogger(failingWriter{}, "IO")
+	n, err := logger.Write([]byte("msg\n"))
+	assert.EqualError(t, err, "disk full")
+	assert.Equal(t, 0, n)
+}
+
+type closeRecorder struct {
+	strings.Builder
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestClose(t *testing.T) {
+	t.Run("closes io.Closer output", func(t *testing.T) {
+		output := &closeRecorder{}
+		logger := NewSmartLogger(output, "APP")
+		require.NoError(t, logger.Close())
+		assert.True(t, output.closed)
+	})
+
+	t.Run("does not close stdout", func(t *testing.T) {
+		logger := NewSmartLogger(os.Stdout, "APP")
+		require.NoError(t, logger.Close())
+		_, err := os.Stdout.Stat()
+		assert.NoError(t, err)
+	})
+
+	t.Run("plain writer", func(t *testing.T) {
+		logger, _ := newTestLogger("APP")
+		assert.NoError(t, logger.Close())
+	})
+}
+
+func TestCounters(t *testing.T) {
+	logger, _ := newTestLogger("APP")
+	assert.Equal(t, 0, logger.GetLogCount())
+
+	logger.Info("1")
+	logger.Warn("2")
+	logger.Error("3")
+	assert.Equal(t, 3, logger.GetLogCount())
+
+	logger.Reset()
+	assert.Equal(t, 0, logger.GetLogCount())
+
+	logger.Info("4")
+	assert.Equal(t, 1, logger.GetLogCount())
+}
+
+func TestNilOutput(t *testing.T) {
+	logger := NewSmartLogger(nil, "APP")
+	assert.NotPanics(t, func() { logger.Info("ignored") })
+	assert.Equal(t, 1, logger.GetLogCount())
+}
+
+func TestStringers(t *testing.T) {
+	logger, _ := newTestLogger("APP")
+	logger.SetLevel(Warn)
+	logger.Warn("w")
+
+	assert.Equal(t, "SmartLogger{prefix: 'APP', level: WARN, logs: 1, errors: 0, bytes: 34, rate: 0.02/s}",
+		logger.String())
+	assert.Equal(t, `SmartLogger{prefix: "APP", level: WARN, logCount: 1, isColor: false, `+
+		`stats: "logs: 1 [WARN=1], bytes: 34, rate: 0.02/s, last error: -"}`,
+		fmt.Sprintf("%#v", logger))
+}
@@ -0,0 +1,128 @@
+// Package smartloggertest содержит помощники для тестов кода, который
+// пишет в SmartLogger: логгер с RingSink и проверки сохраненных записей.
+package smartloggertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	smartlogger "example/src/seminar3/tasks/smart_logger"
+)
+
+// Емкость RingSink, создаваемого NewLogger
+const ringCapacity = 1000
+
+// NewLogger создает логгер для тестов: записи всех уровней попадают
+// в RingSink, основной вывод отключен, Fatal не завершает программу.
+// Если тест упал, сохраненные записи выводятся через t.Log.
+func NewLogger(t testing.TB) (*smartlogger.SmartLogger, *smartlogger.RingSink) {
+	t.Helper()
+	ring := smartlogger.NewRingSink(ringCapacity, smartlogger.Trace)
+	logger := smartlogger.NewSmartLogger(nil, "", smartlogger.WithSink(ring), smartlogger.WithExitFunc(func(int) {}))
+	logger.SetLevel(smartlogger.Trace)
+
+	t.Cleanup(func() {
+		if t.Failed() && ring.Len() > 0 {
+			t.Logf("записи лога:\n%s", strings.Join(ring.Lines(), "\n"))
+		}
+	})
+	return logger, ring
+}
+
+// AssertLines проверяет, что записи в виде "LEVEL message key=value"
+// (см. RingSink.Lines) совпадают с want. При расхождении тест помечается
+// упавшим, а в сообщении выводится построчный diff.
+func AssertLines(t testing.TB, ring *smartlogger.RingSink, want ...string) bool {
+	t.Helper()
+	got := ring.Lines()
+	if equalLines(want, got) {
+		return true
+	}
+	t.Errorf("записи лога не совпадают (- ожидалось, + получено):\n%s", lineDiff(want, got))
+	return false
+}
+
+// AssertLogged проверяет, что есть запись уровня level, сообщение
+// которой содержит substr. Иначе выводит все сохраненные записи.
+func AssertLogged(t testing.TB, ring *smartlogger.RingSink, level smartlogger.Level, substr string) bool {
+	t.Helper()
+	for _, entry := range ring.ByLevel(level) {
+		if strings.Contains(entry.Message, substr) {
+			return true
+		}
+	}
+	t.Errorf("нет записи %s с %q среди записей лога:\n%s", level, substr, indentLines(ring.Lines()))
+	return false
+}
+
+// AssertNotLogged проверяет, что нет записей уровня level и выше
+func AssertNotLogged(t testing.TB, ring *smartlogger.RingSink, level smartlogger.Level) bool {
+	t.Helper()
+	found := ring.Filter(func(entry *smartlogger.Entry) bool { return entry.Level >= level })
+	if len(found) == 0 {
+		return true
+	}
+	lines := make([]string, len(found))
+	for i := range found {
+		lines[i] = found[i].Line()
+	}
+	t.Errorf("неожиданные записи уровня %s и выше:\n%s", level, indentLines(lines))
+	return false
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func indentLines(lines []string) string {
+	if len(lines) == 0 {
+		return "  (пусто)"
+	}
+	return "  " + strings.Join(lines, "\n  ")
+}
+
+// lineDiff строит построчный diff по наибольшей общей подпоследовательности:
+// совпадающие строки с отступом, удаленные с "- ", добавленные с "+ "
+func lineDiff(want, got []string) string {
+	// lcs[i][j] — длина общей подпоследовательности want[i:] и got[j:]
+	lcs := make([][]int, len(want)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(got)+1)
+	}
+	for i := len(want) - 1; i >= 0; i-- {
+		for j := len(got) - 1; j >= 0; j-- {
+			if want[i] == got[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var b strings.Builder
+	i, j := 0, 0
+	for i < len(want) || j < len(got) {
+		switch {
+		case i < len(want) && j < len(got) && want[i] == got[j]:
+			fmt.Fprintf(&b, "  %s\n", want[i])
+			i++
+			j++
+		case i < len(want) && (j == len(got) || lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&b, "- %s\n", want[i])
+			i++
+		default:
+			fmt.Fprintf(&b, "+ %s\n", got[j])
+			j++
+		}
+	}
+	return b.String()
+}
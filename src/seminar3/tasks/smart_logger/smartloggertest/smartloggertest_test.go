@@ -0,0 +1,96 @@
+package smartloggertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	smartlogger "example/src/seminar3/tasks/smart_logger"
+)
+
+// fakeTB записывает сообщения об ошибках вместо того, чтобы ронять тест
+type fakeTB struct {
+	testing.TB
+	errors   []string
+	logs     []string
+	cleanups []func()
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) Logf(format string, args ...interface{}) {
+	f.logs = append(f.logs, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+
+func (f *fakeTB) Failed() bool { return len(f.errors) > 0 }
+
+func (f *fakeTB) finish() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestAssertLinesDiff(t *testing.T) {
+	tb := &fakeTB{}
+	logger, ring := NewLogger(tb)
+
+	logger.Info("started")
+	logger.Warnw("slow", "ms", 1200)
+	logger.Info("stopped")
+
+	assert.True(t, AssertLines(tb, ring, "INFO started", "WARN slow ms=1200", "INFO stopped"))
+	assert.Empty(t, tb.errors)
+
+	assert.False(t, AssertLines(tb, ring, "INFO started", "WARN slow ms=900", "INFO stopped"))
+	require.Len(t, tb.errors, 1)
+	assert.Equal(t, "записи лога не совпадают (- ожидалось, + получено):\n"+
+		"  INFO started\n"+
+		"- WARN slow ms=900\n"+
+		"+ WARN slow ms=1200\n"+
+		"  INFO stopped\n", tb.errors[0])
+
+	tb.finish()
+	require.Len(t, tb.logs, 1, "упавший тест выводит записи лога")
+	assert.Equal(t, "записи лога:\nINFO started\nWARN slow ms=1200\nINFO stopped", tb.logs[0])
+}
+
+func TestAssertLogged(t *testing.T) {
+	tb := &fakeTB{}
+	logger, ring := NewLogger(tb)
+	logger.Errorw("db timeout", "after", "5s")
+
+	assert.True(t, AssertLogged(tb, ring, smartlogger.Error, "timeout"))
+	assert.False(t, AssertLogged(tb, ring, smartlogger.Warn, "timeout"))
+	require.Len(t, tb.errors, 1)
+	assert.Equal(t, "нет записи WARN с \"timeout\" среди записей лога:\n  ERROR db timeout after=5s", tb.errors[0])
+
+	assert.False(t, AssertNotLogged(tb, ring, smartlogger.Error))
+	assert.True(t, strings.HasPrefix(tb.errors[1], "неожиданные записи уровня ERROR и выше:\n  ERROR db timeout"))
+}
+
+func TestNewLoggerPassingTestIsQuiet(t *testing.T) {
+	tb := &fakeTB{}
+	logger, ring := NewLogger(tb)
+	logger.Trace("t")
+	logger.Fatal("не завершает программу")
+
+	AssertLines(tb, ring, "TRACE t", "FATAL не завершает программу")
+	tb.finish()
+	assert.Empty(t, tb.errors)
+	assert.Empty(t, tb.logs)
+}
+
+func TestLineDiff(t *testing.T) {
+	assert.Equal(t, "- a\n  b\n+ c\n", lineDiff([]string{"a", "b"}, []string{"b", "c"}))
+	assert.Equal(t, "+ x\n", lineDiff(nil, []string{"x"}))
+	assert.Equal(t, "", lineDiff(nil, nil))
+}
@@ -0,0 +1,162 @@
+package smartlogger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Окно для расчета темпа записи по умолчанию
+const defaultStatsWindow = time.Minute
+
+// Stats снимок статистики логгера. Возвращается методом SmartLogger.Stats
+// и не меняется при дальнейшей записи.
+type Stats struct {
+	Total     int           // записано записей
+	ByLevel   map[Level]int // записано записей по уровням
+	Bytes     int64         // байт записано в основной вывод
+	LastError time.Time     // время последней записи уровня Error и выше
+	Rate      float64       // записей в секунду за последнее окно
+	Window    time.Duration // длина окна для Rate
+	Sampled   int           // отброшено выборкой
+	Collapsed int           // схлопнуто как повторы
+	Filtered  int           // отброшено хуками
+	Dropped   int64         // отброшено асинхронной очередью
+}
+
+// Errors возвращает число записей уровня Error и выше
+func (s Stats) Errors() int {
+	total := 0
+	for level, n := range s.ByLevel {
+		if level >= Error {
+			total += n
+		}
+	}
+	return total
+}
+
+func (s Stats) String() string {
+	levels := make([]Level, 0, len(s.ByLevel))
+	for level := range s.ByLevel {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+
+	parts := make([]string, 0, len(levels))
+	for _, level := range levels {
+		parts = append(parts, fmt.Sprintf("%s=%d", level, s.ByLevel[level]))
+	}
+
+	lastError := "-"
+	if !s.LastError.IsZero() {
+		lastError = s.LastError.Format(time.RFC3339)
+	}
+	return fmt.Sprintf("logs: %d [%s], bytes: %d, rate: %.2f/s, last error: %s",
+		s.Total, strings.Join(parts, " "), s.Bytes, s.Rate, lastError)
+}
+
+// statsCounter накапливает статистику логгера. Используется под мьютексом.
+type statsCounter struct {
+	total     int
+	byLevel   map[Level]int
+	bytes     int64
+	lastError time.Time
+
+	// скользящее окно из посекундных корзин
+	buckets []int
+	seconds []int64
+}
+
+func newStatsCounter(window time.Duration) statsCounter {
+	n := int(window / time.Second)
+	if n < 1 {
+		n = 1
+	}
+	return statsCounter{
+		byLevel: make(map[Level]int),
+		buckets: make([]int, n),
+		seconds: make([]int64, n),
+	}
+}
+
+// WithStatsWindow задает окно, за которое считается темп записи, по умолчанию 1m.
+// Окно округляется до целых секунд.
+func WithStatsWindow(window time.Duration) Option {
+	return func(sl *SmartLogger) {
+		sl.stats = newStatsCounter(window)
+	}
+}
+
+func (c *statsCounter) add(level Level, now time.Time) {
+	c.total++
+	c.byLevel[level]++
+	if level >= Error {
+		c.lastError = now
+	}
+
+	sec := now.Unix()
+	i := int(sec % int64(len(c.buckets)))
+	if i < 0 {
+		i += len(c.buckets)
+	}
+	if c.seconds[i] != sec {
+		c.seconds[i] = sec
+		c.buckets[i] = 0
+	}
+	c.buckets[i]++
+}
+
+func (c *statsCounter) rate(now time.Time) float64 {
+	sec := now.Unix()
+	window := int64(len(c.buckets))
+	count := 0
+	for i, s := range c.seconds {
+		if age := sec - s; age >= 0 && age < window {
+			count += c.buckets[i]
+		}
+	}
+	return float64(count) / float64(window)
+}
+
+func (c *statsCounter) reset() {
+	*c = newStatsCounter(time.Duration(len(c.buckets)) * time.Second)
+}
+
+// countEntry учитывает записанную запись в статистике. Вызывается под мьютексом.
+func (sl *SmartLogger) countEntry(entry *Entry) {
+	sl.stats.add(entry.Level, sl.now())
+}
+
+// Stats возвращает снимок статистики логгера
+func (sl *SmartLogger) Stats() Stats {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	return sl.snapshot()
+}
+
+// snapshot собирает Stats. Вызывается под мьютексом.
+func (sl *SmartLogger) snapshot() Stats {
+	byLevel := make(map[Level]int, len(sl.stats.byLevel))
+	for level, n := range sl.stats.byLevel {
+		byLevel[level] = n
+	}
+
+	stats := Stats{
+		Total:     sl.stats.total,
+		ByLevel:   byLevel,
+		Bytes:     sl.stats.bytes,
+		LastError: sl.stats.lastError,
+		Rate:      sl.stats.rate(sl.now()),
+		Window:    time.Duration(len(sl.stats.buckets)) * time.Second,
+		Filtered:  sl.filtered,
+	}
+	if sl.sampler != nil {
+		stats.Sampled = sl.sampler.sampled
+		stats.Collapsed = sl.sampler.collapsed
+	}
+	if async, ok := sl.output.(*AsyncWriter); ok {
+		stats.Dropped = async.Dropped()
+	}
+	return stats
+}
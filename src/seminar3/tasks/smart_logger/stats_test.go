@@ -0,0 +1,91 @@
+package smartlogger
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsByLevel(t *testing.T) {
+	var buf strings.Builder
+	clock := &fakeClock{t: testTime}
+	logger := NewSmartLogger(&buf, "APP", WithClock(clock.Now))
+	logger.SetLevel(Debug)
+
+	logger.Debug("d")
+	logger.Info("i")
+	logger.Write([]byte("через Write\n"))
+	clock.Advance(time.Second)
+	logger.Error("e")
+
+	stats := logger.Stats()
+	assert.Equal(t, 4, stats.Total)
+	assert.Equal(t, map[Level]int{Debug: 1, Info: 2, Error: 1}, stats.ByLevel)
+	assert.Equal(t, 1, stats.Errors())
+	assert.Equal(t, int64(buf.Len()), stats.Bytes)
+	assert.Equal(t, testTime.Add(time.Second), stats.LastError)
+	assert.Equal(t, fmt.Sprintf("logs: 4 [DEBUG=1 INFO=2 ERROR=1], bytes: %d, rate: 0.07/s, last error: %s",
+		buf.Len(), stats.LastError.Format(time.RFC3339)), stats.String())
+}
+
+func TestStatsSlidingWindow(t *testing.T) {
+	clock := &fakeClock{t: testTime}
+	logger := NewSmartLogger(nil, "", WithClock(clock.Now), WithStatsWindow(10*time.Second))
+
+	for i := 0; i < 20; i++ {
+		logger.Info("tick")
+	}
+	assert.Equal(t, 10*time.Second, logger.Stats().Window)
+	assert.InDelta(t, 2.0, logger.Stats().Rate, 1e-9)
+
+	clock.Advance(5 * time.Second)
+	logger.Info("tick")
+	assert.InDelta(t, 2.1, logger.Stats().Rate, 1e-9)
+
+	// первые 20 записей вышли из окна
+	clock.Advance(5 * time.Second)
+	assert.InDelta(t, 0.1, logger.Stats().Rate, 1e-9)
+
+	clock.Advance(time.Hour)
+	assert.Zero(t, logger.Stats().Rate)
+	assert.Equal(t, 21, logger.Stats().Total)
+}
+
+func TestStatsSnapshotIsCopy(t *testing.T) {
+	logger, _ := newTestLogger("APP")
+	logger.Info("1")
+
+	stats := logger.Stats()
+	logger.Info("2")
+
+	assert.Equal(t, 1, stats.Total)
+	assert.Equal(t, 1, stats.ByLevel[Info])
+}
+
+func TestStatsReset(t *testing.T) {
+	logger, _ := newTestLogger("APP")
+	logger.Error("boom")
+
+	logger.Reset()
+
+	stats := logger.Stats()
+	assert.Zero(t, stats.Total)
+	assert.Zero(t, stats.Bytes)
+	assert.Empty(t, stats.ByLevel)
+	assert.True(t, stats.LastError.IsZero())
+	assert.Zero(t, stats.Rate)
+	assert.Equal(t, time.Minute, stats.Window)
+}
+
+func TestStatsIncludesSampling(t *testing.T) {
+	logger, _ := newTestLogger("", WithSampling(SamplingConfig{Initial: 1}))
+	logger.Info("x")
+	logger.Info("x")
+
+	stats := logger.Stats()
+	assert.Equal(t, 1, stats.Total)
+	assert.Equal(t, 1, stats.Sampled)
+}
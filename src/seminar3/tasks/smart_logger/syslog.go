@@ -0,0 +1,251 @@
+package smartlogger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Facility источник сообщения syslog (RFC 5424, раздел 6.2.1).
+// Нулевое значение (kern) зарезервировано за ядром и заменяется на FacilityUser.
+type Facility int
+
+const (
+	FacilityUser   Facility = 1
+	FacilityDaemon Facility = 3
+	FacilityAuth   Facility = 4
+	FacilityLocal0 Facility = 16
+	FacilityLocal1 Facility = 17
+	FacilityLocal2 Facility = 18
+	FacilityLocal3 Facility = 19
+	FacilityLocal4 Facility = 20
+	FacilityLocal5 Facility = 21
+	FacilityLocal6 Facility = 22
+	FacilityLocal7 Facility = 23
+)
+
+// Важность сообщения syslog (RFC 5424, раздел 6.2.1)
+const (
+	severityCritical = 2
+	severityError    = 3
+	severityWarning  = 4
+	severityInfo     = 6
+	severityDebug    = 7
+)
+
+const (
+	defaultSyslogNetwork = "unixgram"
+	defaultSyslogAddr    = "/dev/log"
+	syslogTimeLayout     = "2006-01-02T15:04:05.000000Z07:00"
+	syslogNilValue       = "-"
+	// SD-ID для полей записи. 32473 — номер из RFC 5612, зарезервированный для примеров.
+	syslogFieldsID = "fields@32473"
+	maxSDNameLen   = 32
+)
+
+// SyslogConfig параметры отправки в syslog
+type SyslogConfig struct {
+	Network  string   // "udp", "tcp", "unix" или "unixgram", по умолчанию "unixgram"
+	Addr     string   // адрес сервера, по умолчанию /dev/log
+	Facility Facility // по умолчанию FacilityUser
+	Hostname string   // по умолчанию os.Hostname()
+	AppName  string   // по умолчанию имя исполняемого файла
+	Net      NetConfig
+}
+
+// SyslogSink отправляет записи в syslog в формате RFC 5424.
+// Поля записи передаются структурированными данными, префикс логгера — как MSGID.
+// Для потоковых сетей (tcp, unix) сообщения разделяются подсчетом длины по RFC 6587.
+type SyslogSink struct {
+	mu       sync.Mutex
+	w        *NetWriter
+	level    Level
+	facility Facility
+	hostname string
+	appName  string
+	procID   string
+	framed   bool
+	buf      bytes.Buffer
+}
+
+// NewSyslogSink создает приемник уровня level и выше. Подключение
+// происходит при первой записи, поэтому сервер может стартовать позже.
+func NewSyslogSink(config SyslogConfig, level Level) *SyslogSink {
+	if config.Network == "" {
+		config.Network = defaultSyslogNetwork
+	}
+	if config.Addr == "" {
+		config.Addr = defaultSyslogAddr
+	}
+	if config.Facility == 0 {
+		config.Facility = FacilityUser
+	}
+	if config.Hostname == "" {
+		config.Hostname, _ = os.Hostname()
+	}
+	if config.AppName == "" {
+		config.AppName = filepath.Base(os.Args[0])
+	}
+
+	return &SyslogSink{
+		w:        NewNetWriter(config.Network, config.Addr, config.Net),
+		level:    level,
+		facility: config.Facility,
+		hostname: syslogHeaderValue(config.Hostname, 255),
+		appName:  syslogHeaderValue(config.AppName, 48),
+		procID:   strconv.Itoa(os.Getpid()),
+		framed:   isStreamNetwork(config.Network),
+	}
+}
+
+func (s *SyslogSink) Enabled(level Level) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return level >= s.level
+}
+
+func (s *SyslogSink) WriteEntry(entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buf.Reset()
+	s.format(&s.buf, entry)
+	if s.framed {
+		msg := append([]byte(nil), s.buf.Bytes()...)
+		s.buf.Reset()
+		s.buf.WriteString(strconv.Itoa(len(msg)))
+		s.buf.WriteByte(' ')
+		s.buf.Write(msg)
+	}
+	_, err := s.w.Write(s.buf.Bytes())
+	return err
+}
+
+func (s *SyslogSink) Close() error {
+	return s.w.Close()
+}
+
+// format пишет сообщение вида
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG
+func (s *SyslogSink) format(buf *bytes.Buffer, entry *Entry) {
+	fmt.Fprintf(buf, "<%d>1 ", int(s.facility)*8+syslogSeverity(entry.Level))
+	if entry.Time.IsZero() {
+		buf.WriteString(syslogNilValue)
+	} else {
+		buf.WriteString(entry.Time.Format(syslogTimeLayout))
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(s.hostname)
+	buf.WriteByte(' ')
+	buf.WriteString(s.appName)
+	buf.WriteByte(' ')
+	buf.WriteString(s.procID)
+	buf.WriteByte(' ')
+	buf.WriteString(syslogHeaderValue(entry.Prefix, 32))
+	buf.WriteByte(' ')
+	writeStructuredData(buf, entry)
+	if entry.Message != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(strings.TrimRight(entry.Message, "\n"))
+	}
+}
+
+// syslogSeverity сопоставляет уровень логгера с важностью syslog.
+// В syslog нет уровня ниже debug, поэтому Trace тоже становится debug.
+func syslogSeverity(level Level) int {
+	switch {
+	case level >= Fatal:
+		return severityCritical
+	case level >= Error:
+		return severityError
+	case level >= Warn:
+		return severityWarning
+	case level >= Info:
+		return severityInfo
+	default:
+		return severityDebug
+	}
+}
+
+// writeStructuredData пишет поля записи как один элемент
+// [fields@32473 key="value" ...] или "-", если полей нет
+func writeStructuredData(buf *bytes.Buffer, entry *Entry) {
+	if len(entry.Fields) == 0 && entry.Caller == "" {
+		buf.WriteString(syslogNilValue)
+		return
+	}
+	buf.WriteString("[" + syslogFieldsID)
+	if entry.Caller != "" {
+		writeSDParam(buf, CallerKey, entry.Caller)
+	}
+	writeSDFields(buf, "", entry.Fields)
+	buf.WriteByte(']')
+}
+
+func writeSDFields(buf *bytes.Buffer, group string, fields []Field) {
+	for _, field := range fields {
+		key := field.Key
+		if group != "" {
+			key = group + "." + key
+		}
+		if sub, ok := field.Value.([]Field); ok {
+			writeSDFields(buf, key, sub)
+			continue
+		}
+		writeSDParam(buf, key, fmt.Sprint(field.Value))
+	}
+}
+
+// writeSDParam пишет key="value". Недопустимые в SD-NAME символы
+// заменяются на "_", в значении экранируются '"', '\' и ']'.
+func writeSDParam(buf *bytes.Buffer, key, value string) {
+	buf.WriteByte(' ')
+	name := []byte(key)
+	if len(name) > maxSDNameLen {
+		name = name[:maxSDNameLen]
+	}
+	for i, c := range name {
+		if c <= ' ' || c >= 127 || c == '=' || c == ']' || c == '"' {
+			name[i] = '_'
+		}
+	}
+	buf.Write(name)
+	buf.WriteString(`="`)
+	for _, r := range value {
+		if r == '"' || r == '\\' || r == ']' {
+			buf.WriteByte('\\')
+		}
+		buf.WriteRune(r)
+	}
+	buf.WriteByte('"')
+}
+
+// syslogHeaderValue приводит значение к PRINTUSASCII без пробелов
+// не длиннее limit; пустое значение заменяется на "-"
+func syslogHeaderValue(s string, limit int) string {
+	if s == "" {
+		return syslogNilValue
+	}
+	b := []byte(s)
+	if len(b) > limit {
+		b = b[:limit]
+	}
+	for i, c := range b {
+		if c <= ' ' || c >= 127 {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+func isStreamNetwork(network string) bool {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return true
+	}
+	return false
+}
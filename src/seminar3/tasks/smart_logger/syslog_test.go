@@ -0,0 +1,165 @@
+package smartlogger
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSyslogTestLogger(t *testing.T, network, addr string) *SmartLogger {
+	t.Helper()
+	sink := NewSyslogSink(SyslogConfig{
+		Network:  network,
+		Addr:     addr,
+		Facility: FacilityLocal0,
+		Hostname: "host",
+		AppName:  "weather",
+	}, Debug)
+	logger := NewSmartLogger(nil, "API", WithSink(sink), WithClock(testClock))
+	logger.SetLevel(Trace)
+	t.Cleanup(func() { logger.Close() })
+	return logger
+}
+
+// readPackets читает n датаграмм из conn
+func readPackets(t *testing.T, conn net.PacketConn, n int) []string {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var packets []string
+	buf := make([]byte, 4096)
+	for i := 0; i < n; i++ {
+		size, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		packets = append(packets, string(buf[:size]))
+	}
+	return packets
+}
+
+func TestSyslogSinkUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	logger := newSyslogTestLogger(t, "udp", conn.LocalAddr().String())
+	logger.Trace("ниже уровня приемника")
+	logger.Infow("запрос обработан", "status", 200, Group("http", F("method", "GET")))
+	logger.Error("сбой")
+
+	pid := strconv.Itoa(os.Getpid())
+	packets := readPackets(t, conn, 2)
+	assert.Equal(t, `<134>1 2024-03-15T10:30:45.000000Z host weather `+pid+
+		` API [fields@32473 status="200" http.method="GET"] запрос обработан`, packets[0])
+	assert.Equal(t, `<131>1 2024-03-15T10:30:45.000000Z host weather `+pid+` API - сбой`, packets[1])
+}
+
+func TestSyslogSinkTCPFraming(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	messages := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// RFC 6587: "LEN SP MSG" без разделителя строк
+		r := bufio.NewReader(conn)
+		for {
+			var size int
+			if _, err := fmt.Fscanf(r, "%d ", &size); err != nil {
+				return
+			}
+			msg := make([]byte, size)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				return
+			}
+			messages <- string(msg)
+		}
+	}()
+
+	logger := newSyslogTestLogger(t, "tcp", ln.Addr().String())
+	logger.Warn("многострочное\nсообщение")
+	logger.Debug("второе")
+
+	assert.True(t, strings.HasSuffix(<-messages, " API - многострочное\nсообщение"))
+	second := <-messages
+	assert.True(t, strings.HasPrefix(second, "<135>1 "), second)
+	assert.True(t, strings.HasSuffix(second, " API - второе"), second)
+}
+
+func TestSyslogSinkUnixgram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenPacket("unixgram", path)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	logger := newSyslogTestLogger(t, "unixgram", path)
+	logger.Info("локально")
+
+	packets := readPackets(t, conn, 1)
+	assert.Contains(t, packets[0], " API - локально")
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  int
+	}{
+		{Trace, 7},
+		{Debug, 7},
+		{Info, 6},
+		{Warn, 4},
+		{Error, 3},
+		{Fatal, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, syslogSeverity(tt.level))
+		})
+	}
+}
+
+func TestSyslogFormatEscaping(t *testing.T) {
+	s := NewSyslogSink(SyslogConfig{Hostname: "my host", AppName: "app"}, Info)
+	var buf bytes.Buffer
+	s.format(&buf, &Entry{
+		Level:   Info,
+		Message: "готово\n",
+		Fields:  []Field{F(`say "hi"`, `a"b\c]d`)},
+	})
+	assert.Equal(t, `<14>1 - my_host app `+strconv.Itoa(os.Getpid())+
+		` - [fields@32473 say__hi_="a\"b\\c\]d"] готово`, buf.String())
+}
+
+func TestSyslogSinkUnavailable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	var sinkErrors []error
+	sink := NewSyslogSink(SyslogConfig{Network: "tcp", Addr: addr}, Info)
+	logger := NewSmartLogger(nil, "", WithSink(sink), WithSinkErrorHandler(func(_ Sink, err error) {
+		sinkErrors = append(sinkErrors, err)
+	}))
+	defer logger.Close()
+
+	logger.Info("первая")
+	logger.Info("вторая")
+	require.Len(t, sinkErrors, 2, "недоступный сервер не роняет логгер")
+	assert.ErrorContains(t, sinkErrors[0], "ошибка подключения")
+	assert.ErrorIs(t, sinkErrors[1], ErrNotConnected)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"example/src/seminar3/tasks/weather/alert"
+)
+
+// stringList флаг, который можно указать несколько раз
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// runAlert проверяет правила для городов и отправляет уведомления
+func runAlert(args []string) error {
+	fs := flag.NewFlagSet("alert", flag.ContinueOnError)
+	var rules, webhooks, files stringList
+	fs.Var(&rules, "rule", `правило, например "temperature < -20" (можно указать несколько раз)`)
+	fs.Var(&webhooks, "webhook", "URL для POST уведомлений (можно указать несколько раз)")
+	fs.Var(&files, "file", "файл для записи уведомлений (можно указать несколько раз)")
+	every := fs.Duration("every", 5*time.Minute, "интервал опроса")
+	debug := fs.Bool("debug", false, "выводить тайминги HTTP запросов в stderr")
+
+	cities, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(cities) == 0 || len(rules) == 0 {
+		return fmt.Errorf("использование: weather alert <город>... --rule <правило> [--every 5m]")
+	}
+
+	scheduler := alert.NewScheduler(newService(*debug), *every)
+	for _, city := range cities {
+		scheduler.AddCity(city)
+	}
+	for _, source := range rules {
+		rule, err := alert.ParseRule(source)
+		if err != nil {
+			return err
+		}
+		scheduler.AddRule(rule)
+	}
+
+	scheduler.AddSink(alert.NewStdoutSink())
+	for _, url := range webhooks {
+		scheduler.AddSink(alert.NewWebhookSink(url))
+	}
+	for _, path := range files {
+		scheduler.AddSink(alert.NewFileSink(path))
+	}
+	scheduler.SetErrorHandler(func(err error) {
+		fmt.Printf("⚠️  %v\n", err)
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Проверяю %d правил(а) для: %s\n", len(rules), strings.Join(cities, ", "))
+	return scheduler.Run(ctx)
+}
@@ -0,0 +1,260 @@
+package alert
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+// Поля WeatherData, доступные в правилах. Числовые поля совпадают с метриками domain.
+const (
+	FieldTemperature = domain.MetricTemperature
+	FieldFeelsLike   = domain.MetricFeelsLike
+	FieldHumidity    = domain.MetricHumidity
+	FieldWind        = domain.MetricWind
+	FieldDescription = "description"
+)
+
+// Операторы сравнения
+const (
+	OpLess         = "<"
+	OpLessEqual    = "<="
+	OpGreater      = ">"
+	OpGreaterEqual = ">="
+	OpEqual        = "=="
+	OpNotEqual     = "!="
+	OpContains     = "contains"
+)
+
+// fieldAliases сопоставляет допустимые написания поля с его каноническим именем
+var fieldAliases = map[string]string{
+	"temperature": FieldTemperature,
+	"temp":        FieldTemperature,
+	"feels_like":  FieldFeelsLike,
+	"feels":       FieldFeelsLike,
+	"humidity":    FieldHumidity,
+	"wind":        FieldWind,
+	"wind_speed":  FieldWind,
+	"description": FieldDescription,
+	"desc":        FieldDescription,
+}
+
+// fieldUnits перечисляет единицы измерения, допустимые для числовых полей
+var fieldUnits = map[string][]string{
+	FieldTemperature: {"c", "°c"},
+	FieldFeelsLike:   {"c", "°c"},
+	FieldHumidity:    {"%"},
+	FieldWind:        {"km/h", "kmh", "км/ч"},
+}
+
+// ParseError ошибка разбора правила с указанием позиции
+type ParseError struct {
+	Rule string
+	Pos  int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("правило %q, позиция %d: %s", e.Rule, e.Pos+1, e.Msg)
+}
+
+// Rule условие над domain.WeatherData, например "wind > 50 km/h"
+type Rule struct {
+	Source string
+	Field  string
+	Op     string
+	Number float64
+	Text   string
+}
+
+// ParseRule разбирает правило вида "<поле> <оператор> <значение> [единица]"
+func ParseRule(source string) (*Rule, error) {
+	p := &parser{src: source}
+	rule := &Rule{Source: strings.TrimSpace(source)}
+
+	p.skipSpaces()
+	start := p.pos
+	name := p.readWord()
+	if name == "" {
+		return nil, p.errorf(start, "ожидалось имя поля")
+	}
+	field, ok := fieldAliases[strings.ToLower(name)]
+	if !ok {
+		return nil, p.errorf(start, "неизвестное поле %q", name)
+	}
+	rule.Field = field
+
+	p.skipSpaces()
+	start = p.pos
+	op := p.readOperator()
+	if op == "" {
+		return nil, p.errorf(start, "ожидался оператор сравнения")
+	}
+	if err := p.checkOperator(field, op, start); err != nil {
+		return nil, err
+	}
+	rule.Op = op
+
+	p.skipSpaces()
+	start = p.pos
+	if field == FieldDescription {
+		text := strings.TrimSpace(p.src[p.pos:])
+		text = strings.Trim(text, `"'`)
+		if text == "" {
+			return nil, p.errorf(start, "ожидалось текстовое значение")
+		}
+		rule.Text = text
+		return rule, nil
+	}
+
+	numStr := p.readNumber()
+	if numStr == "" {
+		return nil, p.errorf(start, "ожидалось число")
+	}
+	number, err := strconv.ParseFloat(numStr, 64)
+	if err != nil {
+		return nil, p.errorf(start, "некорректное число %q", numStr)
+	}
+	rule.Number = number
+
+	p.skipSpaces()
+	start = p.pos
+	unit := strings.TrimSpace(p.src[p.pos:])
+	if unit != "" && !slices.Contains(fieldUnits[field], strings.ToLower(unit)) {
+		return nil, p.errorf(start, "недопустимая единица %q для поля %s", unit, field)
+	}
+
+	return rule, nil
+}
+
+// MustParseRule как ParseRule, но паникует при ошибке
+func MustParseRule(source string) *Rule {
+	rule, err := ParseRule(source)
+	if err != nil {
+		panic(err)
+	}
+	return rule
+}
+
+// Match проверяет, выполняется ли правило для данных о погоде
+func (r *Rule) Match(data *domain.WeatherData) bool {
+	if data == nil {
+		return false
+	}
+
+	if r.Field == FieldDescription {
+		description := strings.ToLower(data.Description)
+		text := strings.ToLower(r.Text)
+		switch r.Op {
+		case OpContains:
+			return strings.Contains(description, text)
+		case OpEqual:
+			return description == text
+		case OpNotEqual:
+			return description != text
+		}
+		return false
+	}
+
+	value := r.value(data)
+	switch r.Op {
+	case OpLess:
+		return value < r.Number
+	case OpLessEqual:
+		return value <= r.Number
+	case OpGreater:
+		return value > r.Number
+	case OpGreaterEqual:
+		return value >= r.Number
+	case OpEqual:
+		return value == r.Number
+	case OpNotEqual:
+		return value != r.Number
+	}
+	return false
+}
+
+func (r *Rule) String() string {
+	return r.Source
+}
+
+// value возвращает значение числового поля; поле проверено при разборе
+func (r *Rule) value(data *domain.WeatherData) float64 {
+	value, _ := data.Metric(r.Field)
+	return value
+}
+
+// parser посимвольно разбирает правило, запоминая позицию для ошибок
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorf(pos int, format string, args ...interface{}) *ParseError {
+	return &ParseError{Rule: p.src, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *parser) readWord() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) readOperator() string {
+	for _, op := range []string{OpLessEqual, OpGreaterEqual, OpEqual, OpNotEqual, OpLess, OpGreater} {
+		if strings.HasPrefix(p.src[p.pos:], op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	if strings.HasPrefix(p.src[p.pos:], "=") {
+		p.pos++
+		return OpEqual
+	}
+	return strings.ToLower(p.readWord())
+}
+
+func (p *parser) readNumber() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if (c < '0' || c > '9') && c != '.' && c != '-' && c != '+' {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) checkOperator(field, op string, pos int) error {
+	switch op {
+	case OpEqual, OpNotEqual:
+		return nil
+	case OpContains:
+		if field != FieldDescription {
+			return p.errorf(pos, "оператор contains применим только к полю description")
+		}
+		return nil
+	case OpLess, OpLessEqual, OpGreater, OpGreaterEqual:
+		if field == FieldDescription {
+			return p.errorf(pos, "оператор %s неприменим к текстовому полю description", op)
+		}
+		return nil
+	}
+	return p.errorf(pos, "неизвестный оператор %q", op)
+}
@@ -0,0 +1,88 @@
+package alert
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		field  string
+		op     string
+		number float64
+		text   string
+	}{
+		{"negative temperature", "temperature < -20", FieldTemperature, OpLess, -20, ""},
+		{"no spaces", "temp<=-5.5", FieldTemperature, OpLessEqual, -5.5, ""},
+		{"wind with unit", "wind > 50 km/h", FieldWind, OpGreater, 50, ""},
+		{"humidity with percent", "humidity >= 90 %", FieldHumidity, OpGreaterEqual, 90, ""},
+		{"single equals", "feels_like = 0", FieldFeelsLike, OpEqual, 0, ""},
+		{"contains", "description contains snow", FieldDescription, OpContains, 0, "snow"},
+		{"quoted text", `desc == "Light rain"`, FieldDescription, OpEqual, 0, "Light rain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRule(tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.field, rule.Field)
+			assert.Equal(t, tt.op, rule.Op)
+			assert.Equal(t, tt.number, rule.Number)
+			assert.Equal(t, tt.text, rule.Text)
+		})
+	}
+}
+
+func TestParseRuleErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		pos    int
+	}{
+		{"empty", "", 0},
+		{"unknown field", "pressure > 1000", 0},
+		{"missing operator", "temperature 20", 12},
+		{"unknown operator", "temperature like 20", 12},
+		{"missing number", "wind >", 6},
+		{"bad number", "wind > 5-0", 7},
+		{"wrong unit", "wind > 50 %", 10},
+		{"contains on number", "humidity contains 5", 9},
+		{"compare text", "description > snow", 12},
+		{"missing text", "description contains", 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRule(tt.source)
+			var parseErr *ParseError
+			require.True(t, errors.As(err, &parseErr), "ожидалась ParseError, получено %v", err)
+			assert.Equal(t, tt.pos, parseErr.Pos)
+		})
+	}
+}
+
+func TestRuleMatch(t *testing.T) {
+	data := &domain.WeatherData{
+		Temperature: -25,
+		FeelsLike:   -31,
+		Humidity:    80,
+		WindSpeed:   55,
+		Description: "Heavy snow",
+	}
+
+	assert.True(t, MustParseRule("temperature < -20").Match(data))
+	assert.False(t, MustParseRule("temperature > -20").Match(data))
+	assert.True(t, MustParseRule("wind > 50 km/h").Match(data))
+	assert.True(t, MustParseRule("humidity == 80").Match(data))
+	assert.True(t, MustParseRule("feels_like <= -31").Match(data))
+	assert.True(t, MustParseRule("description contains SNOW").Match(data))
+	assert.False(t, MustParseRule("description == snow").Match(data))
+	assert.False(t, MustParseRule("temperature < 0").Match(nil))
+}
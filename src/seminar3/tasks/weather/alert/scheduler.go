@@ -0,0 +1,108 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"example/src/seminar3/tasks/weather/client"
+)
+
+// Scheduler периодически проверяет правила для списка городов.
+// Уведомление отправляется только при переходе правила в сработавшее состояние,
+// поэтому одно и то же условие не повторяется на каждом опросе. Состояние
+// ведется отдельно для каждого приемника: если доставка не удалась, уведомление
+// повторяется на следующем опросе, пока условие выполняется.
+type Scheduler struct {
+	provider client.WeatherProvider
+	cities   []string
+	rules    []*Rule
+	sinks    []Sink
+	interval time.Duration
+	active   map[string]bool // правило уже доставлено приемнику: город, правило, номер приемника
+	now      func() time.Time
+	onError  func(error)
+}
+
+func NewScheduler(provider client.WeatherProvider, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		provider: provider,
+		interval: interval,
+		active:   make(map[string]bool),
+		now:      time.Now,
+	}
+}
+
+func (s *Scheduler) AddCity(city string) {
+	s.cities = append(s.cities, city)
+}
+
+func (s *Scheduler) AddRule(rule *Rule) {
+	s.rules = append(s.rules, rule)
+}
+
+func (s *Scheduler) AddSink(sink Sink) {
+	s.sinks = append(s.sinks, sink)
+}
+
+// SetErrorHandler задает обработчик ошибок, возникших при фоновом опросе
+func (s *Scheduler) SetErrorHandler(fn func(error)) {
+	s.onError = fn
+}
+
+// Evaluate выполняет один цикл проверки всех правил
+func (s *Scheduler) Evaluate(ctx context.Context) error {
+	var errs []error
+	for _, city := range s.cities {
+		data, err := s.provider.GetWeather(city)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", city, err))
+			continue
+		}
+
+		for _, rule := range s.rules {
+			matched := rule.Match(data)
+			alert := Alert{Time: s.now(), City: city, Rule: rule.Source, Data: data}
+			for i, sink := range s.sinks {
+				key := city + "\x00" + rule.Source + "\x00" + strconv.Itoa(i)
+				if !matched {
+					delete(s.active, key)
+					continue
+				}
+				if s.active[key] {
+					continue
+				}
+				if err := sink.Notify(ctx, alert); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", city, err))
+					continue
+				}
+				s.active[key] = true
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run проверяет правила сразу и затем с заданным интервалом, пока не отменен ctx
+func (s *Scheduler) Run(ctx context.Context) error {
+	if s.interval <= 0 {
+		return fmt.Errorf("интервал опроса должен быть положительным, получено %v", s.interval)
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Evaluate(ctx); err != nil && s.onError != nil && ctx.Err() == nil {
+			s.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+type stubProvider struct {
+	data *domain.WeatherData
+	err  error
+}
+
+func (p *stubProvider) GetWeather(city string) (*domain.WeatherData, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	data := *p.data
+	data.City = city
+	return &data, nil
+}
+
+type recordingSink struct {
+	alerts []Alert
+}
+
+func (s *recordingSink) Notify(_ context.Context, alert Alert) error {
+	s.alerts = append(s.alerts, alert)
+	return nil
+}
+
+func TestSchedulerDeduplicates(t *testing.T) {
+	provider := &stubProvider{data: &domain.WeatherData{Temperature: -25}}
+	sink := &recordingSink{}
+
+	s := NewScheduler(provider, time.Minute)
+	s.AddCity("Moscow")
+	s.AddRule(MustParseRule("temperature < -20"))
+	s.AddSink(sink)
+
+	ctx := context.Background()
+	require.NoError(t, s.Evaluate(ctx))
+	require.NoError(t, s.Evaluate(ctx))
+	assert.Len(t, sink.alerts, 1, "повторный опрос не должен дублировать уведомление")
+
+	provider.data.Temperature = -10
+	require.NoError(t, s.Evaluate(ctx))
+	assert.Len(t, sink.alerts, 1)
+
+	provider.data.Temperature = -30
+	require.NoError(t, s.Evaluate(ctx))
+	require.Len(t, sink.alerts, 2, "после восстановления правило снова срабатывает")
+	assert.Equal(t, "Moscow", sink.alerts[1].City)
+	assert.Equal(t, "temperature < -20", sink.alerts[1].Rule)
+}
+
+// flakySink не доставляет первые failures уведомлений
+type flakySink struct {
+	recordingSink
+	failures int
+}
+
+func (s *flakySink) Notify(ctx context.Context, alert Alert) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("вебхук недоступен")
+	}
+	return s.recordingSink.Notify(ctx, alert)
+}
+
+func TestSchedulerRetriesFailedDelivery(t *testing.T) {
+	provider := &stubProvider{data: &domain.WeatherData{Temperature: -25}}
+	flaky := &flakySink{failures: 1}
+	stable := &recordingSink{}
+
+	s := NewScheduler(provider, time.Minute)
+	s.AddCity("Moscow")
+	s.AddRule(MustParseRule("temperature < -20"))
+	s.AddSink(flaky)
+	s.AddSink(stable)
+
+	ctx := context.Background()
+	assert.ErrorContains(t, s.Evaluate(ctx), "вебхук недоступен")
+	assert.Empty(t, flaky.alerts)
+	assert.Len(t, stable.alerts, 1)
+
+	require.NoError(t, s.Evaluate(ctx))
+	assert.Len(t, flaky.alerts, 1, "неудачная доставка повторяется, пока условие выполняется")
+	assert.Len(t, stable.alerts, 1, "доставленное уведомление не дублируется")
+
+	require.NoError(t, s.Evaluate(ctx))
+	assert.Len(t, flaky.alerts, 1)
+}
+
+func TestSchedulerReportsProviderErrors(t *testing.T) {
+	s := NewScheduler(&stubProvider{err: errors.New("нет сети")}, time.Minute)
+	s.AddCity("Moscow")
+	s.AddRule(MustParseRule("temperature < -20"))
+
+	assert.ErrorContains(t, s.Evaluate(context.Background()), "нет сети")
+}
+
+func TestSinks(t *testing.T) {
+	alert := Alert{
+		Time: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		City: "Moscow",
+		Rule: "wind > 50",
+		Data: &domain.WeatherData{City: "Moscow", WindSpeed: 60, Description: "Windy"},
+	}
+	ctx := context.Background()
+
+	t.Run("writer", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, NewWriterSink(&buf).Notify(ctx, alert))
+		assert.Contains(t, buf.String(), `Moscow: сработало правило "wind > 50"`)
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		var received Alert
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		}))
+		defer server.Close()
+
+		require.NoError(t, NewWebhookSink(server.URL).Notify(ctx, alert))
+		assert.Equal(t, alert.Rule, received.Rule)
+	})
+
+	t.Run("webhook error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		assert.Error(t, NewWebhookSink(server.URL).Notify(ctx, alert))
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "alerts.jsonl")
+		sink := NewFileSink(path)
+		require.NoError(t, sink.Notify(ctx, alert))
+		require.NoError(t, sink.Notify(ctx, alert))
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, 2, bytes.Count(content, []byte("\n")))
+	})
+}
@@ -0,0 +1,118 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+// Alert сработавшее правило
+type Alert struct {
+	Time time.Time           `json:"time"`
+	City string              `json:"city"`
+	Rule string              `json:"rule"`
+	Data *domain.WeatherData `json:"data"`
+}
+
+// Message возвращает человекочитаемое описание уведомления
+func (a Alert) Message() string {
+	return fmt.Sprintf("%s: сработало правило %q (%.1f°C, ветер %.1f км/ч, %s)",
+		a.City, a.Rule, a.Data.Temperature, a.Data.WindSpeed, a.Data.Description)
+}
+
+// Sink получатель уведомлений
+type Sink interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// WriterSink пишет уведомления в io.Writer, например в stdout
+type WriterSink struct {
+	w io.Writer
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+func NewStdoutSink() *WriterSink {
+	return NewWriterSink(os.Stdout)
+}
+
+func (s *WriterSink) Notify(_ context.Context, alert Alert) error {
+	_, err := fmt.Fprintf(s.w, "🚨 [%s] %s\n", alert.Time.Format("15:04:05"), alert.Message())
+	return err
+}
+
+// WebhookSink отправляет уведомления POST-запросом с JSON телом
+type WebhookSink struct {
+	client *http.Client
+	url    string
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		client: &http.Client{Timeout: 10 * time.Second},
+		url:    url,
+	}
+}
+
+func (s *WebhookSink) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации уведомления: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка отправки вебхука: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("вебхук вернул ошибку: %s", resp.Status)
+	}
+	return nil
+}
+
+// FileSink дописывает уведомления в файл по одному JSON объекту на строку
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (s *FileSink) Notify(_ context.Context, alert Alert) error {
+	line, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации уведомления: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла уведомлений: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
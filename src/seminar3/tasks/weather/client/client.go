@@ -1,12 +1,15 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
+	smartlogger "example/src/seminar3/tasks/smart_logger"
 	"example/src/seminar3/tasks/weather/domain"
 )
 
@@ -20,6 +23,10 @@ const (
 type WttrInProvider struct {
 	client  *http.Client
 	baseURL string
+
+	tracing bool
+	mu      sync.Mutex
+	last    *Diagnostics
 }
 
 func NewWttrInProvider() *WttrInProvider {
@@ -29,8 +36,103 @@ func NewWttrInProvider() *WttrInProvider {
 	}
 }
 
-// GetWeather получает данные о погоде с retry логикой
+// EnableTracing включает сбор таймингов HTTP запросов
+func (w *WttrInProvider) EnableTracing() {
+	w.tracing = true
+}
+
+// LastDiagnostics возвращает диагностику последнего вызова GetWeather.
+// Возвращает nil, если трассировка выключена. При одновременных вызовах
+// это может быть диагностика чужого запроса — в таком случае используйте
+// GetWeatherWithDiagnostics.
+func (w *WttrInProvider) LastDiagnostics() *Diagnostics {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.last
+}
+
+// progress сообщает о ходе попыток получить погоду
+type progress interface {
+	retry(attempt int, delay time.Duration)
+	failed(attempt int, err error)
+	done(attempt int)
+}
+
+// consoleProgress печатает ход попыток в stdout для CLI
+type consoleProgress struct{}
+
+func (consoleProgress) retry(attempt int, delay time.Duration) {
+	fmt.Printf("Повторная попытка %d/%d через %v...\n", attempt, maxRetries, delay)
+}
+
+func (consoleProgress) failed(attempt int, err error) {
+	fmt.Printf("Попытка %d неудачна: %v\n", attempt, err)
+}
+
+func (consoleProgress) done(attempt int) {
+	fmt.Printf("Данные успешно получены (попытка %d)\n", attempt)
+}
+
+// logProgress пишет ход попыток в логгер из контекста,
+// поэтому записи получают поля запроса, например request_id
+type logProgress struct {
+	logger *smartlogger.SmartLogger
+}
+
+func (p logProgress) retry(attempt int, delay time.Duration) {
+	p.logger.Debugw("повторная попытка", "attempt", attempt, "max", maxRetries, "delay", delay)
+}
+
+func (p logProgress) failed(attempt int, err error) {
+	p.logger.Warnw("попытка неудачна", "attempt", attempt, "err", err)
+}
+
+func (p logProgress) done(attempt int) {
+	p.logger.Debugw("данные получены", "attempt", attempt)
+}
+
+// GetWeather получает данные о погоде с retry логикой.
+// Ход попыток печатается в stdout, см. также GetWeatherContext.
 func (w *WttrInProvider) GetWeather(city string) (*domain.WeatherData, error) {
+	return w.get(context.Background(), city, consoleProgress{})
+}
+
+// GetWeatherContext как GetWeather, но ход попыток пишется в логгер
+// из ctx (smartlogger.FromContext), а запрос отменяется вместе с ctx
+func (w *WttrInProvider) GetWeatherContext(ctx context.Context, city string) (*domain.WeatherData, error) {
+	return w.get(ctx, city, logProgress{logger: smartlogger.FromContext(ctx).With("city", city)})
+}
+
+func (w *WttrInProvider) get(ctx context.Context, city string, report progress) (*domain.WeatherData, error) {
+	if !w.tracing {
+		return w.getWeather(ctx, city, nil, report)
+	}
+
+	diag := &Diagnostics{City: city}
+	data, err := w.getWeather(ctx, city, diag, report)
+	w.mu.Lock()
+	w.last = diag
+	w.mu.Unlock()
+	return data, err
+}
+
+// GetWeatherWithDiagnostics получает погоду и возвращает тайминги каждой попытки
+func (w *WttrInProvider) GetWeatherWithDiagnostics(city string) (*domain.WeatherData, *Diagnostics, error) {
+	diag := &Diagnostics{City: city}
+	data, err := w.getWeather(context.Background(), city, diag, consoleProgress{})
+	return data, diag, err
+}
+
+// GetWeatherWithDiagnosticsContext как GetWeatherWithDiagnostics,
+// но ход попыток пишется в логгер из ctx
+func (w *WttrInProvider) GetWeatherWithDiagnosticsContext(ctx context.Context, city string) (*domain.WeatherData, *Diagnostics, error) {
+	diag := &Diagnostics{City: city}
+	report := logProgress{logger: smartlogger.FromContext(ctx).With("city", city)}
+	data, err := w.getWeather(ctx, city, diag, report)
+	return data, diag, err
+}
+
+func (w *WttrInProvider) getWeather(ctx context.Context, city string, diag *Diagnostics, report progress) (*domain.WeatherData, error) {
 	if city == "" {
 		return nil, fmt.Errorf("город не может быть пустым")
 	}
@@ -43,37 +145,58 @@ func (w *WttrInProvider) GetWeather(city string) (*domain.WeatherData, error) {
 	// Retry логика
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
-			fmt.Printf("Повторная попытка %d/%d через %v...\n", attempt, maxRetries, retryDelay)
-			time.Sleep(retryDelay)
+			report.retry(attempt, retryDelay)
+			select {
+			case <-time.After(retryDelay):
+			case <-ctx.Done():
+				return nil, fmt.Errorf("запрос погоды прерван: %w", ctx.Err())
+			}
 		}
 
-		body, err := w.makeRequest(url)
+		var tracer *attemptTracer
+		if diag != nil {
+			tracer = newAttemptTracer(attempt + 1)
+		}
+
+		body, err := w.makeRequest(ctx, url, tracer)
 		if err != nil {
 			lastError = err
-			fmt.Printf("Попытка %d неудачна: %v\n", attempt+1, err)
+			if tracer != nil {
+				diag.Attempts = append(diag.Attempts, tracer.result(nil))
+			}
+			report.failed(attempt+1, err)
 			continue
 		}
 
 		weatherData, err = w.parseResponse(body, city)
+		if tracer != nil {
+			diag.Attempts = append(diag.Attempts, tracer.result(err))
+		}
 		if err != nil {
 			lastError = err
-			fmt.Printf("Попытка %d: ошибка парсинга: %v\n", attempt+1, err)
+			report.failed(attempt+1, err)
 			continue
 		}
-		fmt.Printf("Данные успешно получены (попытка %d)\n", attempt+1)
+		report.done(attempt + 1)
 		return weatherData, nil
 	}
 
 	return nil, fmt.Errorf("не удалось получить данные после %d попыток: %w", maxRetries+1, lastError)
 }
 
-// makeRequest выполняет HTTP запрос с обработкой ошибок
-func (w *WttrInProvider) makeRequest(url string) ([]byte, error) {
-	req, err := http.NewRequest("GET", url, nil)
+// makeRequest выполняет HTTP запрос с обработкой ошибок.
+// Если передан tracer, в него записываются тайминги запроса.
+func (w *WttrInProvider) makeRequest(ctx context.Context, url string, tracer *attemptTracer) (body []byte, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
 	}
 
+	if tracer != nil {
+		req = tracer.withTrace(req)
+		defer func() { tracer.finish(err) }()
+	}
+
 	// Добавляем User-Agent чтобы быть хорошим гражданином интернета
 	req.Header.Set("User-Agent", "WeatherCLI/1.0 (educational project)")
 
@@ -87,7 +210,7 @@ func (w *WttrInProvider) makeRequest(url string) ([]byte, error) {
 		return nil, fmt.Errorf("сервер вернул ошибку: %s", resp.Status)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err = io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка чтения ответа: %w", err)
 	}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	smartlogger "example/src/seminar3/tasks/smart_logger"
+)
+
+// captureStdout возвращает все, что fn напечатала в os.Stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	require.NoError(t, w.Close())
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestGetWeatherPrintsProgress(t *testing.T) {
+	provider := newTestProvider(t)
+
+	out := captureStdout(t, func() {
+		_, err := provider.GetWeather("Moscow")
+		require.NoError(t, err)
+	})
+	assert.Equal(t, "Данные успешно получены (попытка 1)\n", out)
+}
+
+func TestGetWeatherContextLogsWithRequestFields(t *testing.T) {
+	provider := newTestProvider(t)
+	ring := smartlogger.NewRingSink(10, smartlogger.Trace)
+	logger := smartlogger.NewSmartLogger(nil, "weather", smartlogger.WithSink(ring))
+	logger.SetLevel(smartlogger.Debug)
+	ctx := smartlogger.NewContext(context.Background(), logger.With("request_id", "req-1"))
+
+	service := NewWeatherService(provider)
+	out := captureStdout(t, func() {
+		data, err := service.GetWeatherContext(ctx, "Moscow")
+		require.NoError(t, err)
+		assert.Equal(t, 5.0, data.Temperature)
+	})
+
+	assert.Empty(t, out, "в режиме сервера stdout не используется")
+	assert.Equal(t, []string{"DEBUG данные получены request_id=req-1 city=Moscow attempt=1"}, ring.Lines())
+}
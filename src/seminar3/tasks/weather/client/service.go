@@ -1,6 +1,8 @@
 package client
 
 import (
+	"context"
+
 	"example/src/seminar3/tasks/weather/domain"
 )
 
@@ -9,6 +11,21 @@ type WeatherProvider interface {
 	GetWeather(city string) (*domain.WeatherData, error)
 }
 
+// ContextWeatherProvider провайдер, который пишет свой лог через логгер
+// из контекста и прерывает запрос при его отмене
+type ContextWeatherProvider interface {
+	GetWeatherContext(ctx context.Context, city string) (*domain.WeatherData, error)
+}
+
+// GetWeatherContext вызывает GetWeatherContext провайдера, если он его
+// поддерживает, и обычный GetWeather в противном случае
+func GetWeatherContext(ctx context.Context, provider WeatherProvider, city string) (*domain.WeatherData, error) {
+	if p, ok := provider.(ContextWeatherProvider); ok {
+		return p.GetWeatherContext(ctx, city)
+	}
+	return provider.GetWeather(city)
+}
+
 // WeatherService основной сервис
 type WeatherService struct {
 	provider WeatherProvider
@@ -21,3 +38,8 @@ func NewWeatherService(provider WeatherProvider) *WeatherService {
 func (w *WeatherService) GetWeather(city string) (*domain.WeatherData, error) {
 	return w.provider.GetWeather(city)
 }
+
+// GetWeatherContext получает погоду в рамках запроса ctx, см. ContextWeatherProvider
+func (w *WeatherService) GetWeatherContext(ctx context.Context, city string) (*domain.WeatherData, error) {
+	return GetWeatherContext(ctx, w.provider, city)
+}
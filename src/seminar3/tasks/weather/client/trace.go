@@ -0,0 +1,120 @@
+package client
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AttemptTrace тайминги одной попытки HTTP запроса
+type AttemptTrace struct {
+	Attempt      int
+	DNS          time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	FirstByte    time.Duration // от начала запроса до первого байта ответа
+	Total        time.Duration // включая чтение тела ответа
+	ReusedConn   bool
+	Err          error
+}
+
+// Diagnostics диагностика одного вызова GetWeather
+type Diagnostics struct {
+	City     string
+	Attempts []AttemptTrace
+}
+
+func (d *Diagnostics) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Диагностика запроса для %s:\n", d.City)
+	for _, a := range d.Attempts {
+		fmt.Fprintf(&b, "  попытка %d: dns=%v connect=%v tls=%v ttfb=%v total=%v",
+			a.Attempt, a.DNS, a.Connect, a.TLSHandshake, a.FirstByte, a.Total)
+		if a.ReusedConn {
+			b.WriteString(" (соединение переиспользовано)")
+		}
+		if a.Err != nil {
+			fmt.Fprintf(&b, " ошибка: %v", a.Err)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// attemptTracer собирает тайминги через httptrace.ClientTrace.
+// Колбэки вызываются из горутин транспорта: при Happy Eyeballs несколько
+// подключений идут одновременно, а после таймаута client.Do подключение
+// может завершиться уже после возврата. Поэтому поля защищены мьютексом,
+// а в Diagnostics попадает копия, снятая после finish.
+type attemptTracer struct {
+	mu           sync.Mutex
+	trace        AttemptTrace
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+}
+
+func newAttemptTracer(attempt int) *attemptTracer {
+	return &attemptTracer{trace: AttemptTrace{Attempt: attempt}, start: time.Now()}
+}
+
+// withTrace привязывает трассировку к запросу
+func (t *attemptTracer) withTrace(req *http.Request) *http.Request {
+	ct := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.update(func() { t.dnsStart = time.Now() }) },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.update(func() { t.trace.DNS = time.Since(t.dnsStart) })
+		},
+		ConnectStart: func(string, string) {
+			t.update(func() {
+				if t.connectStart.IsZero() {
+					t.connectStart = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(string, string, error) {
+			t.update(func() { t.trace.Connect = time.Since(t.connectStart) })
+		},
+		TLSHandshakeStart: func() { t.update(func() { t.tlsStart = time.Now() }) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.update(func() { t.trace.TLSHandshake = time.Since(t.tlsStart) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.update(func() { t.trace.ReusedConn = info.Reused })
+		},
+		GotFirstResponseByte: func() {
+			t.update(func() { t.trace.FirstByte = time.Since(t.start) })
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), ct))
+}
+
+func (t *attemptTracer) update(fn func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fn()
+}
+
+func (t *attemptTracer) finish(err error) {
+	t.update(func() {
+		t.trace.Total = time.Since(t.start)
+		t.trace.Err = err
+	})
+}
+
+// result возвращает копию собранных таймингов.
+// Если err не nil, он заменяет ошибку запроса, например ошибкой разбора ответа.
+func (t *attemptTracer) result(err error) AttemptTrace {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	trace := t.trace
+	if err != nil {
+		trace.Err = err
+	}
+	return trace
+}
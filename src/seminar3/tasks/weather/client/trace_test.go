@@ -0,0 +1,93 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testResponse = `{
+	"current_condition": [{
+		"temp_C": "5", "humidity": "70", "windspeedKmph": "10", "FeelsLikeC": "2",
+		"weatherDesc": [{"value": "Cloudy"}]
+	}],
+	"nearest_area": [{"areaName": [{"value": "Moscow"}]}]
+}`
+
+func newTestProvider(t *testing.T) *WttrInProvider {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testResponse))
+	}))
+	t.Cleanup(server.Close)
+
+	provider := NewWttrInProvider()
+	provider.client = server.Client()
+	provider.baseURL = server.URL + "/%s"
+	return provider
+}
+
+func TestGetWeatherWithDiagnostics(t *testing.T) {
+	provider := newTestProvider(t)
+
+	data, diag, err := provider.GetWeatherWithDiagnostics("Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, data.Temperature)
+
+	require.Len(t, diag.Attempts, 1)
+	attempt := diag.Attempts[0]
+	assert.Equal(t, 1, attempt.Attempt)
+	assert.NoError(t, attempt.Err)
+	assert.Positive(t, attempt.Connect)
+	assert.Positive(t, attempt.TLSHandshake)
+	assert.Positive(t, attempt.FirstByte)
+	assert.GreaterOrEqual(t, attempt.Total, attempt.FirstByte)
+	assert.Contains(t, diag.String(), "попытка 1")
+}
+
+func TestLastDiagnostics(t *testing.T) {
+	provider := newTestProvider(t)
+
+	_, err := provider.GetWeather("Moscow")
+	require.NoError(t, err)
+	assert.Nil(t, provider.LastDiagnostics(), "без EnableTracing диагностика не собирается")
+
+	provider.EnableTracing()
+	_, err = provider.GetWeather("Moscow")
+	require.NoError(t, err)
+
+	diag := provider.LastDiagnostics()
+	require.NotNil(t, diag)
+	require.Len(t, diag.Attempts, 1)
+	assert.True(t, diag.Attempts[0].ReusedConn)
+}
+
+func TestAttemptTracerConcurrentCallbacks(t *testing.T) {
+	tracer := newAttemptTracer(1)
+	req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	require.NoError(t, err)
+	ct := httptrace.ContextClientTrace(tracer.withTrace(req).Context())
+
+	// Happy Eyeballs: подключения по IPv4 и IPv6 идут одновременно,
+	// а последнее может завершиться уже после finish
+	var wg sync.WaitGroup
+	for _, addr := range []string{"[::1]:443", "127.0.0.1:443"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ct.ConnectStart("tcp", addr)
+			ct.ConnectDone("tcp", addr, nil)
+		}()
+	}
+	tracer.finish(nil)
+	trace := tracer.result(nil)
+	wg.Wait()
+
+	// одновременный доступ к полям проверяет go test -race
+	assert.Equal(t, 1, trace.Attempt)
+	assert.NoError(t, trace.Err)
+}
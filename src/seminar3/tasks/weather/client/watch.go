@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+// Reading одно показание, полученное в режиме наблюдения
+type Reading struct {
+	Time     time.Time
+	Data     *domain.WeatherData
+	Previous *domain.WeatherData
+	Err      error
+}
+
+// Change возвращает изменения относительно предыдущего успешного показания
+func (r Reading) Change() domain.Change {
+	return domain.Diff(r.Previous, r.Data)
+}
+
+// Watch опрашивает сервис с заданным интервалом и передает каждое показание в fn.
+// Опрос идет через GetWeather, поэтому на него действуют все обертки провайдера.
+// Возвращает nil, когда ctx отменен.
+func (w *WeatherService) Watch(
+	ctx context.Context,
+	city string,
+	interval time.Duration,
+	fn func(Reading),
+) error {
+	if interval <= 0 {
+		return fmt.Errorf("интервал опроса должен быть положительным, получено %v", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous *domain.WeatherData
+	for {
+		data, err := w.fetch(ctx, city)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		fn(Reading{Time: time.Now(), Data: data, Previous: previous, Err: err})
+		if err == nil {
+			previous = data
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// fetch выполняет запрос, не блокируя отмену контекста.
+// Провайдер не принимает контекст, поэтому запрос дорабатывает в фоне.
+func (w *WeatherService) fetch(ctx context.Context, city string) (*domain.WeatherData, error) {
+	type result struct {
+		data *domain.WeatherData
+		err  error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		data, err := w.GetWeather(city)
+		done <- result{data: data, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		return r.data, r.err
+	}
+}
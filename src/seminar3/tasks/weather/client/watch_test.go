@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+type sequenceProvider struct {
+	results []*domain.WeatherData
+	calls   int
+}
+
+func (p *sequenceProvider) GetWeather(city string) (*domain.WeatherData, error) {
+	defer func() { p.calls++ }()
+	if p.calls >= len(p.results) || p.results[p.calls] == nil {
+		return nil, errors.New("нет данных")
+	}
+	return p.results[p.calls], nil
+}
+
+func TestWatch(t *testing.T) {
+	provider := &sequenceProvider{results: []*domain.WeatherData{
+		{City: "Moscow", Temperature: 10, Description: "Sunny"},
+		nil,
+		{City: "Moscow", Temperature: 12.5, Description: "Cloudy"},
+	}}
+	service := NewWeatherService(provider)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var readings []Reading
+	err := service.Watch(ctx, "Moscow", time.Millisecond, func(r Reading) {
+		readings = append(readings, r)
+		if len(readings) == 3 {
+			cancel()
+		}
+	})
+	require.NoError(t, err)
+	require.Len(t, readings, 3)
+
+	assert.True(t, readings[0].Change().IsZero())
+	assert.Error(t, readings[1].Err)
+
+	change := readings[2].Change()
+	assert.Equal(t, 2.5, change.TemperatureDelta)
+	assert.True(t, change.DescriptionChanged)
+	assert.Equal(t, "Sunny", change.PreviousDescription)
+}
+
+func TestWatchInvalidInterval(t *testing.T) {
+	service := NewWeatherService(&sequenceProvider{})
+	err := service.Watch(context.Background(), "Moscow", 0, func(Reading) {})
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"example/src/seminar3/tasks/weather/domain"
+	"example/src/seminar3/tasks/weather/render"
+)
+
+// runCompare выводит погоду в нескольких городах рядом
+func runCompare(args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	sortBy := fs.String("sort", "", "сортировка: temperature, feels_like, humidity, wind")
+	ascending := fs.Bool("asc", false, "сортировать по возрастанию")
+	format := fs.String("format", render.FormatTable, "формат вывода: table, csv, json")
+	debug := fs.Bool("debug", false, "выводить тайминги HTTP запросов в stderr")
+
+	cities, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(cities) < 2 {
+		return fmt.Errorf("использование: weather compare <город> <город>... [--sort temperature] [--format table]")
+	}
+
+	renderer, err := render.New(*format)
+	if err != nil {
+		return err
+	}
+
+	service := newService(*debug)
+	comparison := domain.NewComparison()
+	for _, city := range cities {
+		data, err := service.GetWeather(city)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: %v\n", city, err)
+			continue
+		}
+		comparison.Rows = append(comparison.Rows, data)
+	}
+	if len(comparison.Rows) == 0 {
+		return fmt.Errorf("не удалось получить погоду ни для одного города")
+	}
+
+	if *sortBy != "" {
+		if err := comparison.Sort(*sortBy, *ascending); err != nil {
+			return err
+		}
+	}
+
+	if *format == render.FormatTable {
+		fmt.Println()
+	}
+	return renderer.Render(os.Stdout, comparison)
+}
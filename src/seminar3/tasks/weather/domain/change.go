@@ -0,0 +1,33 @@
+package domain
+
+// Change описывает разницу между двумя последовательными показаниями
+type Change struct {
+	TemperatureDelta    float64
+	FeelsLikeDelta      float64
+	HumidityDelta       int
+	WindSpeedDelta      float64
+	DescriptionChanged  bool
+	PreviousDescription string
+}
+
+// Diff сравнивает текущее показание с предыдущим.
+// Если предыдущего показания нет, возвращается пустое изменение.
+func Diff(prev, cur *WeatherData) Change {
+	if prev == nil || cur == nil {
+		return Change{}
+	}
+
+	return Change{
+		TemperatureDelta:    cur.Temperature - prev.Temperature,
+		FeelsLikeDelta:      cur.FeelsLike - prev.FeelsLike,
+		HumidityDelta:       cur.Humidity - prev.Humidity,
+		WindSpeedDelta:      cur.WindSpeed - prev.WindSpeed,
+		DescriptionChanged:  cur.Description != prev.Description,
+		PreviousDescription: prev.Description,
+	}
+}
+
+// IsZero сообщает, что показания не изменились
+func (c Change) IsZero() bool {
+	return c == Change{}
+}
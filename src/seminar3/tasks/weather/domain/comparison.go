@@ -0,0 +1,84 @@
+package domain
+
+import "sort"
+
+// Extremes минимальное и максимальное значение метрики и города, в которых
+// они достигаются. При равенстве значений в список попадают все такие города.
+type Extremes struct {
+	Min       float64  `json:"min"`
+	Max       float64  `json:"max"`
+	MinCities []string `json:"min_cities"`
+	MaxCities []string `json:"max_cities"`
+}
+
+// IsMin сообщает, равно ли value минимуму. Для метрики, одинаковой
+// во всех городах, крайних значений нет.
+func (e Extremes) IsMin(value float64) bool {
+	return e.Min < e.Max && value == e.Min
+}
+
+// IsMax сообщает, равно ли value максимуму
+func (e Extremes) IsMax(value float64) bool {
+	return e.Min < e.Max && value == e.Max
+}
+
+// Comparison погода в нескольких городах
+type Comparison struct {
+	Rows []*WeatherData
+}
+
+func NewComparison(rows ...*WeatherData) *Comparison {
+	return &Comparison{Rows: rows}
+}
+
+// Sort упорядочивает строки по метрике, по умолчанию по убыванию
+func (c *Comparison) Sort(metric string, ascending bool) error {
+	if _, err := (&WeatherData{}).Metric(metric); err != nil {
+		return err
+	}
+
+	sort.SliceStable(c.Rows, func(i, j int) bool {
+		a, _ := c.Rows[i].Metric(metric)
+		b, _ := c.Rows[j].Metric(metric)
+		if ascending {
+			return a < b
+		}
+		return a > b
+	})
+	return nil
+}
+
+// Extremes возвращает крайние значения каждой метрики.
+// Метрики, одинаковые во всех городах, не попадают в результат.
+func (c *Comparison) Extremes() map[string]Extremes {
+	result := make(map[string]Extremes)
+	if len(c.Rows) < 2 {
+		return result
+	}
+
+	for _, metric := range Metrics {
+		var e Extremes
+		e.Min, _ = c.Rows[0].Metric(metric)
+		e.Max = e.Min
+		for _, row := range c.Rows[1:] {
+			value, _ := row.Metric(metric)
+			e.Min = min(e.Min, value)
+			e.Max = max(e.Max, value)
+		}
+		if e.Min == e.Max {
+			continue
+		}
+
+		for _, row := range c.Rows {
+			value, _ := row.Metric(metric)
+			switch value {
+			case e.Min:
+				e.MinCities = append(e.MinCities, row.City)
+			case e.Max:
+				e.MaxCities = append(e.MaxCities, row.City)
+			}
+		}
+		result[metric] = e
+	}
+	return result
+}
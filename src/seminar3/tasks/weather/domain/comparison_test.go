@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestComparison() *Comparison {
+	return NewComparison(
+		&WeatherData{City: "Moscow", Temperature: -5, FeelsLike: -9, Humidity: 80, WindSpeed: 12},
+		&WeatherData{City: "London", Temperature: 8, FeelsLike: 6, Humidity: 90, WindSpeed: 20},
+		&WeatherData{City: "Tokyo", Temperature: 15, FeelsLike: 15, Humidity: 60, WindSpeed: 20},
+	)
+}
+
+func TestComparisonSort(t *testing.T) {
+	c := newTestComparison()
+
+	require.NoError(t, c.Sort(MetricTemperature, false))
+	assert.Equal(t, "Tokyo", c.Rows[0].City)
+	assert.Equal(t, "Moscow", c.Rows[2].City)
+
+	require.NoError(t, c.Sort(MetricHumidity, true))
+	assert.Equal(t, "Tokyo", c.Rows[0].City)
+	assert.Equal(t, "London", c.Rows[2].City)
+
+	assert.Error(t, c.Sort("pressure", false))
+}
+
+func TestComparisonExtremes(t *testing.T) {
+	extremes := newTestComparison().Extremes()
+	assert.Equal(t, Extremes{Min: -5, Max: 15, MinCities: []string{"Moscow"}, MaxCities: []string{"Tokyo"}},
+		extremes[MetricTemperature])
+	assert.Equal(t, Extremes{Min: 12, Max: 20, MinCities: []string{"Moscow"}, MaxCities: []string{"London", "Tokyo"}},
+		extremes[MetricWind], "при равенстве отмечаются все города")
+
+	wind := extremes[MetricWind]
+	assert.True(t, wind.IsMax(20))
+	assert.True(t, wind.IsMin(12))
+	assert.False(t, wind.IsMax(12))
+
+	same := NewComparison(&WeatherData{City: "A", Humidity: 50}, &WeatherData{City: "B", Humidity: 50}).Extremes()
+	assert.NotContains(t, same, MetricHumidity)
+	assert.False(t, same[MetricHumidity].IsMin(0), "у отсутствующей метрики нет крайних значений")
+}
+
+func TestMetric(t *testing.T) {
+	data := &WeatherData{Temperature: 1, FeelsLike: 2, Humidity: 3, WindSpeed: 4}
+	for i, name := range Metrics {
+		value, err := data.Metric(name)
+		require.NoError(t, err)
+		assert.Equal(t, float64(i+1), value, name)
+	}
+
+	_, err := data.Metric("pressure")
+	assert.EqualError(t, err, `неизвестная метрика "pressure"`)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "fmt"
+
+// Числовые метрики погоды: по ним сравниваются города,
+// считается статистика истории и проверяются правила оповещений
+const (
+	MetricTemperature = "temperature"
+	MetricFeelsLike   = "feels_like"
+	MetricHumidity    = "humidity"
+	MetricWind        = "wind"
+)
+
+// Metrics метрики в порядке вывода колонок
+var Metrics = []string{MetricTemperature, MetricFeelsLike, MetricHumidity, MetricWind}
+
+// Metric возвращает числовое значение метрики по имени
+func (w *WeatherData) Metric(name string) (float64, error) {
+	switch name {
+	case MetricTemperature:
+		return w.Temperature, nil
+	case MetricFeelsLike:
+		return w.FeelsLike, nil
+	case MetricHumidity:
+		return float64(w.Humidity), nil
+	case MetricWind:
+		return w.WindSpeed, nil
+	}
+	return 0, fmt.Errorf("неизвестная метрика %q", name)
+}
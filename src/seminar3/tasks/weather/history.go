@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"example/src/seminar3/tasks/weather/domain"
+	"example/src/seminar3/tasks/weather/history"
+)
+
+// Форматы времени, принимаемые флагами history
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"}
+
+// timeFlag флаг с моментом времени в локальной зоне
+type timeFlag struct {
+	time.Time
+}
+
+func (f *timeFlag) String() string {
+	if f.IsZero() {
+		return ""
+	}
+	return f.Format("2006-01-02 15:04")
+}
+
+func (f *timeFlag) Set(value string) error {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			f.Time = t
+			return nil
+		}
+	}
+	return fmt.Errorf("неизвестный формат времени %q, ожидается например \"2006-01-02 15:04\"", value)
+}
+
+// historyDir возвращает каталог хранения истории.
+// Его можно переопределить переменной окружения WEATHER_HISTORY_DIR.
+func historyDir() (string, error) {
+	if dir := os.Getenv("WEATHER_HISTORY_DIR"); dir != "" {
+		return dir, nil
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("не удалось определить каталог истории: %w", err)
+	}
+	return filepath.Join(cacheDir, "weather", "history"), nil
+}
+
+// runHistory выводит сохраненные показания и статистику по ним
+func runHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	var from, to, at timeFlag
+	fs.Var(&from, "from", "начало интервала, например \"2024-01-01 00:00\"")
+	fs.Var(&to, "to", "конец интервала (не включительно)")
+	fs.Var(&at, "at", "показать запись, ближайшую к указанному моменту")
+	last := fs.Duration("last", 0, "интервал до текущего момента, например 24h")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("использование: weather history <город> [--from T] [--to T] [--last 24h] [--at T]")
+	}
+	city := positional[0]
+
+	dir, err := historyDir()
+	if err != nil {
+		return err
+	}
+	store := history.NewStore(dir)
+
+	if !at.IsZero() {
+		record, err := store.Nearest(city, at.Time)
+		if err != nil {
+			return fmt.Errorf("%s: %w", city, err)
+		}
+		printRecord(*record)
+		return nil
+	}
+
+	if *last > 0 {
+		from.Time = time.Now().Add(-*last)
+	}
+
+	records, err := store.Query(city, from.Time, to.Time)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		fmt.Printf("Нет сохраненных показаний для %s\n", city)
+		return nil
+	}
+
+	fmt.Printf("📚 История погоды в %s (%d записей)\n\n", city, len(records))
+	for _, record := range records {
+		printRecord(record)
+	}
+
+	fmt.Println()
+	for _, metric := range domain.Metrics {
+		stats, err := history.Aggregate(records, metric)
+		if errors.Is(err, history.ErrNoRecords) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-12s мин %6.1f (%s)  макс %6.1f (%s)  сред %6.1f\n",
+			metric,
+			stats.Min, stats.MinAt.Local().Format("02.01 15:04"),
+			stats.Max, stats.MaxAt.Local().Format("02.01 15:04"),
+			stats.Avg)
+	}
+	return nil
+}
+
+func printRecord(record history.Record) {
+	fmt.Printf("%s  %6.1f°C  ощущается %6.1f°C  %3d%%  %5.1f км/ч  %s\n",
+		record.Time.Local().Format("2006-01-02 15:04"),
+		record.Temperature, record.FeelsLike, record.Humidity, record.WindSpeed, record.Description)
+}
@@ -0,0 +1,42 @@
+package history
+
+import "time"
+
+// Stats минимальное, максимальное и среднее значение метрики
+type Stats struct {
+	Metric string
+	Count  int
+	Min    float64
+	MinAt  time.Time
+	Max    float64
+	MaxAt  time.Time
+	Avg    float64
+}
+
+// Aggregate считает статистику по метрике (domain.Metric*) для набора записей
+func Aggregate(records []Record, metric string) (Stats, error) {
+	stats := Stats{Metric: metric}
+	if len(records) == 0 {
+		return stats, ErrNoRecords
+	}
+
+	var sum float64
+	for i, record := range records {
+		value, err := record.Metric(metric)
+		if err != nil {
+			return stats, err
+		}
+
+		if i == 0 || value < stats.Min {
+			stats.Min, stats.MinAt = value, record.Time
+		}
+		if i == 0 || value > stats.Max {
+			stats.Max, stats.MaxAt = value, record.Time
+		}
+		sum += value
+	}
+
+	stats.Count = len(records)
+	stats.Avg = sum / float64(len(records))
+	return stats, nil
+}
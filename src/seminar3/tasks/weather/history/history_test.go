@@ -0,0 +1,88 @@
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+type stubProvider struct {
+	temperature float64
+	err         error
+}
+
+func (p *stubProvider) GetWeather(city string) (*domain.WeatherData, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &domain.WeatherData{City: city, Temperature: p.temperature, Humidity: 50}, nil
+}
+
+func TestRecorder(t *testing.T) {
+	store := NewStore(t.TempDir())
+	provider := &stubProvider{}
+	recorder := NewRecorder(provider, store)
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for hour, temp := range []float64{-5, -2, 1, 3} {
+		recorder.now = func() time.Time { return base.Add(time.Duration(hour) * 6 * time.Hour) }
+		provider.temperature = temp
+		_, err := recorder.GetWeather("Moscow")
+		require.NoError(t, err)
+	}
+
+	provider.err = errors.New("нет сети")
+	_, err := recorder.GetWeather("Moscow")
+	assert.Error(t, err)
+
+	all, err := store.Query("moscow", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, all, 4, "неудачные запросы не сохраняются")
+
+	t.Run("time range", func(t *testing.T) {
+		records, err := store.Query("Moscow", base.Add(6*time.Hour), base.Add(18*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, -2.0, records[0].Temperature)
+		assert.Equal(t, 1.0, records[1].Temperature)
+	})
+
+	t.Run("nearest", func(t *testing.T) {
+		record, err := store.Nearest("Moscow", base.Add(11*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, record.Temperature)
+		assert.True(t, record.Time.Equal(base.Add(12*time.Hour)))
+	})
+
+	t.Run("aggregate", func(t *testing.T) {
+		stats, err := Aggregate(all, domain.MetricTemperature)
+		require.NoError(t, err)
+		assert.Equal(t, 4, stats.Count)
+		assert.Equal(t, -5.0, stats.Min)
+		assert.Equal(t, 3.0, stats.Max)
+		assert.Equal(t, -0.75, stats.Avg)
+		assert.True(t, stats.MaxAt.Equal(base.Add(18*time.Hour)))
+
+		_, err = Aggregate(all, "pressure")
+		assert.Error(t, err)
+	})
+}
+
+func TestStoreEmpty(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	records, err := store.Query("Tokyo", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	_, err = store.Nearest("Tokyo", time.Now())
+	assert.ErrorIs(t, err, ErrNoRecords)
+
+	_, err = Aggregate(records, domain.MetricTemperature)
+	assert.ErrorIs(t, err, ErrNoRecords)
+}
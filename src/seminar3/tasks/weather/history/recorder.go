@@ -0,0 +1,57 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"example/src/seminar3/tasks/weather/client"
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+// Recorder обертка над WeatherProvider, сохраняющая каждый успешный ответ в Store
+type Recorder struct {
+	provider client.WeatherProvider
+	store    *Store
+	now      func() time.Time
+	onError  func(error)
+}
+
+func NewRecorder(provider client.WeatherProvider, store *Store) *Recorder {
+	return &Recorder{
+		provider: provider,
+		store:    store,
+		now:      time.Now,
+	}
+}
+
+// SetErrorHandler задает обработчик ошибок записи.
+// Ошибка записи не прерывает получение погоды.
+func (r *Recorder) SetErrorHandler(fn func(error)) {
+	r.onError = fn
+}
+
+func (r *Recorder) GetWeather(city string) (*domain.WeatherData, error) {
+	data, err := r.provider.GetWeather(city)
+	if err != nil {
+		return nil, err
+	}
+	r.record(city, data)
+	return data, nil
+}
+
+// GetWeatherContext передает ctx провайдеру, если тот его поддерживает
+func (r *Recorder) GetWeatherContext(ctx context.Context, city string) (*domain.WeatherData, error) {
+	data, err := client.GetWeatherContext(ctx, r.provider, city)
+	if err != nil {
+		return nil, err
+	}
+	r.record(city, data)
+	return data, nil
+}
+
+func (r *Recorder) record(city string, data *domain.WeatherData) {
+	record := Record{Time: r.now(), WeatherData: *data}
+	if err := r.store.Append(city, record); err != nil && r.onError != nil {
+		r.onError(err)
+	}
+}
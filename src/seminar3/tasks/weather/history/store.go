@@ -0,0 +1,139 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+// ErrNoRecords возвращается, когда для города нет подходящих записей
+var ErrNoRecords = errors.New("нет записей")
+
+// Record одно сохраненное показание
+type Record struct {
+	Time time.Time `json:"time"`
+	domain.WeatherData
+}
+
+// Store хранит историю в append-only JSONL файлах, по одному на город
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Append дописывает запись в файл города
+func (s *Store) Append(city string, record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("ошибка сериализации записи: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("ошибка создания каталога истории: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path(city), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла истории: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("ошибка записи истории: %w", err)
+	}
+	return nil
+}
+
+// Query возвращает записи города в полуинтервале [from, to).
+// Нулевое значение границы означает отсутствие ограничения.
+func (s *Store) Query(city string, from, to time.Time) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path(city))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла истории: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("ошибка разбора строки %d: %w", lineNum, err)
+		}
+		if !from.IsZero() && record.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !record.Time.Before(to) {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла истории: %w", err)
+	}
+
+	return records, nil
+}
+
+// Nearest возвращает запись, ближайшую по времени к at
+func (s *Store) Nearest(city string, at time.Time) (*Record, error) {
+	records, err := s.Query(city, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrNoRecords
+	}
+
+	best := records[0]
+	for _, record := range records[1:] {
+		if absDuration(record.Time.Sub(at)) < absDuration(best.Time.Sub(at)) {
+			best = record
+		}
+	}
+	return &best, nil
+}
+
+// path возвращает имя файла истории для города
+func (s *Store) path(city string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, strings.TrimSpace(city))
+	return filepath.Join(s.dir, name+".jsonl")
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -1,25 +1,78 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
 
 	"example/src/seminar3/tasks/weather/client"
+	"example/src/seminar3/tasks/weather/domain"
+	"example/src/seminar3/tasks/weather/history"
 )
 
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("Использование: weather <город>")
-		fmt.Println("Пример: weather Moscow")
-		fmt.Println("Пример: weather \"New York\"")
-		fmt.Println("Пример: weather Лондон")
+		printUsage()
 		os.Exit(1)
 	}
 
-	city := os.Args[1]
+	var err error
+	switch os.Args[1] {
+	case "watch":
+		err = runWatch(os.Args[2:])
+	case "alert":
+		err = runAlert(os.Args[2:])
+	case "history":
+		err = runHistory(os.Args[2:])
+	case "compare":
+		err = runCompare(os.Args[2:])
+	case "serve":
+		err = runServe(os.Args[2:])
+	default:
+		err = runShow(os.Args[1:])
+	}
+
+	if err != nil {
+		fmt.Printf("❌ Ошибка: %v\n", err)
+		os.Exit(1)
+	}
+}
 
-	provider := client.NewWttrInProvider()
-	service := client.NewWeatherService(provider)
+func printUsage() {
+	fmt.Println("Использование: weather <город> [--debug]")
+	fmt.Println("               weather watch <город> [--every 5m]")
+	fmt.Println("               weather alert <город>... --rule <правило> [--webhook URL] [--file путь]")
+	fmt.Println("               weather history <город> [--from T] [--to T] [--last 24h] [--at T]")
+	fmt.Println("               weather compare <город> <город>... [--sort temperature] [--format table|csv|json]")
+	fmt.Println("               weather serve [--addr :8080] [--admin-addr 127.0.0.1:8081] [--log-level info]")
+	fmt.Println("Пример: weather Moscow")
+	fmt.Println("Пример: weather \"New York\"")
+	fmt.Println("Пример: weather Лондон")
+	fmt.Println("Пример: weather watch Moscow --every 5m")
+	fmt.Println("Пример: weather alert Moscow --rule \"temperature < -20\" --rule \"wind > 50 km/h\"")
+	fmt.Println("Пример: weather history Moscow --at \"2024-01-01 12:00\"")
+	fmt.Println("Пример: weather compare Moscow London Tokyo --sort wind")
+	fmt.Println("Пример: weather serve --addr :8080  (затем GET /weather/Moscow)")
+	fmt.Println("        уровень логов: kill -USR1 <pid> или, с --admin-addr,")
+	fmt.Println("        PUT http://127.0.0.1:8081/loglevel {\"level\":\"debug\"}")
+}
+
+// runShow однократно запрашивает и выводит погоду
+func runShow(args []string) error {
+	fs := flag.NewFlagSet("weather", flag.ContinueOnError)
+	debug := fs.Bool("debug", false, "выводить тайминги HTTP запросов в stderr")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		printUsage()
+		os.Exit(1)
+	}
+	city := positional[0]
+	service := newService(*debug)
 
 	fmt.Printf("Запрашиваю погоду для города: %s\n", city)
 
@@ -34,4 +87,62 @@ func main() {
 	}
 
 	data.Display()
+	return nil
+}
+
+// newService собирает сервис погоды со всеми обертками провайдера.
+// В режиме debug после каждого запроса в stderr выводятся тайминги HTTP.
+func newService(debug bool) *client.WeatherService {
+	wttr := client.NewWttrInProvider()
+	var provider client.WeatherProvider = wttr
+	if debug {
+		provider = &debugProvider{wttr: wttr}
+	}
+
+	dir, err := historyDir()
+	if err != nil {
+		fmt.Printf("⚠️  История не сохраняется: %v\n", err)
+	} else {
+		recorder := history.NewRecorder(provider, history.NewStore(dir))
+		recorder.SetErrorHandler(func(err error) {
+			fmt.Printf("⚠️  Не удалось сохранить историю: %v\n", err)
+		})
+		provider = recorder
+	}
+
+	return client.NewWeatherService(provider)
+}
+
+// debugProvider печатает диагностику HTTP запросов после каждого вызова.
+// Диагностика берется из самого вызова, поэтому при одновременных
+// запросах (weather serve --debug) тайминги не перепутываются.
+type debugProvider struct {
+	wttr *client.WttrInProvider
+}
+
+func (p *debugProvider) GetWeather(city string) (*domain.WeatherData, error) {
+	data, diag, err := p.wttr.GetWeatherWithDiagnostics(city)
+	fmt.Fprint(os.Stderr, diag)
+	return data, err
+}
+
+func (p *debugProvider) GetWeatherContext(ctx context.Context, city string) (*domain.WeatherData, error) {
+	data, diag, err := p.wttr.GetWeatherWithDiagnosticsContext(ctx, city)
+	fmt.Fprint(os.Stderr, diag)
+	return data, err
+}
+
+// parseArgs разбирает флаги, которые могут идти как до, так и после позиционных аргументов
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
 }
@@ -0,0 +1,132 @@
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+// Форматы вывода
+const (
+	FormatTable = "table"
+	FormatCSV   = "csv"
+	FormatJSON  = "json"
+)
+
+// Маркеры крайних значений в таблице
+const (
+	markerMax = "▲"
+	markerMin = "▼"
+)
+
+// Renderer выводит сравнение погоды в определенном формате
+type Renderer interface {
+	Render(w io.Writer, c *domain.Comparison) error
+}
+
+// New возвращает Renderer для формата
+func New(format string) (Renderer, error) {
+	switch format {
+	case FormatTable:
+		return TableRenderer{}, nil
+	case FormatCSV:
+		return CSVRenderer{}, nil
+	case FormatJSON:
+		return JSONRenderer{}, nil
+	}
+	return nil, fmt.Errorf("неизвестный формат %q, доступны: %s, %s, %s", format, FormatTable, FormatCSV, FormatJSON)
+}
+
+// TableRenderer выводит выровненную таблицу с маркерами крайних значений
+type TableRenderer struct{}
+
+func (TableRenderer) Render(w io.Writer, c *domain.Comparison) error {
+	extremes := c.Extremes()
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Город\tТемпература\tОщущается\tВлажность\tВетер\tОписание")
+	for _, row := range c.Rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			row.City,
+			mark(fmt.Sprintf("%.1f°C", row.Temperature), row.Temperature, extremes[domain.MetricTemperature]),
+			mark(fmt.Sprintf("%.1f°C", row.FeelsLike), row.FeelsLike, extremes[domain.MetricFeelsLike]),
+			mark(fmt.Sprintf("%d%%", row.Humidity), float64(row.Humidity), extremes[domain.MetricHumidity]),
+			mark(fmt.Sprintf("%.1f км/ч", row.WindSpeed), row.WindSpeed, extremes[domain.MetricWind]),
+			row.Description,
+		)
+	}
+	return tw.Flush()
+}
+
+// mark добавляет маркер к text, если value крайнее. Строки сравниваются
+// по значению, поэтому при равенстве отмечаются все города.
+func mark(text string, value float64, e domain.Extremes) string {
+	switch {
+	case e.IsMax(value):
+		return text + " " + markerMax
+	case e.IsMin(value):
+		return text + " " + markerMin
+	}
+	return text
+}
+
+// CSVRenderer выводит сравнение в CSV, крайние значения перечислены в колонке extremes
+type CSVRenderer struct{}
+
+func (CSVRenderer) Render(w io.Writer, c *domain.Comparison) error {
+	extremes := c.Extremes()
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"city", "temperature", "feels_like", "humidity", "wind_speed", "description", "extremes"}); err != nil {
+		return err
+	}
+	for _, row := range c.Rows {
+		var marks []string
+		for _, metric := range domain.Metrics {
+			value, _ := row.Metric(metric)
+			switch {
+			case extremes[metric].IsMax(value):
+				marks = append(marks, "max:"+metric)
+			case extremes[metric].IsMin(value):
+				marks = append(marks, "min:"+metric)
+			}
+		}
+
+		record := []string{
+			row.City,
+			strconv.FormatFloat(row.Temperature, 'f', -1, 64),
+			strconv.FormatFloat(row.FeelsLike, 'f', -1, 64),
+			strconv.Itoa(row.Humidity),
+			strconv.FormatFloat(row.WindSpeed, 'f', -1, 64),
+			row.Description,
+			strings.Join(marks, ";"),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// JSONRenderer выводит сравнение одним JSON объектом
+type JSONRenderer struct{}
+
+func (JSONRenderer) Render(w io.Writer, c *domain.Comparison) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Cities   []*domain.WeatherData      `json:"cities"`
+		Extremes map[string]domain.Extremes `json:"extremes"`
+	}{
+		Cities:   c.Rows,
+		Extremes: c.Extremes(),
+	})
+}
@@ -0,0 +1,92 @@
+package render
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+func newTestComparison() *domain.Comparison {
+	return domain.NewComparison(
+		&domain.WeatherData{City: "Moscow", Temperature: -5, FeelsLike: -9, Humidity: 80, WindSpeed: 12, Description: "Snow"},
+		&domain.WeatherData{City: "London", Temperature: 8, FeelsLike: 6, Humidity: 90, WindSpeed: 20, Description: "Rain"},
+		&domain.WeatherData{City: "Tokyo", Temperature: 15, FeelsLike: 15, Humidity: 60, WindSpeed: 20, Description: "Clear"},
+	)
+}
+
+func TestTableRenderer(t *testing.T) {
+	var buf strings.Builder
+	require.NoError(t, TableRenderer{}.Render(&buf, newTestComparison()))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Contains(t, lines[1], "-5.0°C "+markerMin)
+	assert.Contains(t, lines[3], "15.0°C "+markerMax)
+	assert.Contains(t, lines[2], "90% "+markerMax)
+	assert.Contains(t, lines[2], "20.0 км/ч "+markerMax, "равные максимумы отмечаются в каждой строке")
+	assert.Contains(t, lines[3], "20.0 км/ч "+markerMax)
+
+	// Колонки выровнены: описание начинается в одной позиции
+	column := func(line, s string) int {
+		return utf8.RuneCountInString(line[:strings.Index(line, s)])
+	}
+	assert.Equal(t, column(lines[1], "Snow"), column(lines[2], "Rain"))
+}
+
+func TestCSVRenderer(t *testing.T) {
+	var buf strings.Builder
+	require.NoError(t, CSVRenderer{}.Render(&buf, newTestComparison()))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "city,temperature,feels_like,humidity,wind_speed,description,extremes", lines[0])
+	assert.Equal(t, "Moscow,-5,-9,80,12,Snow,min:temperature;min:feels_like;min:wind", lines[1])
+	assert.Equal(t, "Tokyo,15,15,60,20,Clear,max:temperature;max:feels_like;min:humidity;max:wind", lines[3])
+}
+
+func TestTableRendererSameCityName(t *testing.T) {
+	// разные запросы могут вернуть одно и то же название города
+	c := domain.NewComparison(
+		&domain.WeatherData{City: "Moscow", Temperature: -5},
+		&domain.WeatherData{City: "Moscow", Temperature: 3},
+	)
+	var buf strings.Builder
+	require.NoError(t, TableRenderer{}.Render(&buf, c))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[1], "-5.0°C "+markerMin)
+	assert.NotContains(t, lines[1], markerMax)
+	assert.Contains(t, lines[2], "3.0°C "+markerMax)
+	assert.NotContains(t, lines[2], markerMin)
+}
+
+func TestJSONRenderer(t *testing.T) {
+	var buf strings.Builder
+	require.NoError(t, JSONRenderer{}.Render(&buf, newTestComparison()))
+
+	var decoded struct {
+		Cities   []domain.WeatherData       `json:"cities"`
+		Extremes map[string]domain.Extremes `json:"extremes"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(buf.String()), &decoded))
+	assert.Len(t, decoded.Cities, 3)
+	assert.Equal(t, domain.Extremes{Min: -5, Max: 15, MinCities: []string{"Moscow"}, MaxCities: []string{"Tokyo"}},
+		decoded.Extremes[domain.MetricTemperature])
+	assert.Equal(t, []string{"London", "Tokyo"}, decoded.Extremes[domain.MetricWind].MaxCities)
+}
+
+func TestNew(t *testing.T) {
+	for _, format := range []string{FormatTable, FormatCSV, FormatJSON} {
+		_, err := New(format)
+		assert.NoError(t, err)
+	}
+	_, err := New("xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	smartlogger "example/src/seminar3/tasks/smart_logger"
+	"example/src/seminar3/tasks/weather/server"
+)
+
+// Сколько ждать завершения текущих запросов при остановке сервера
+const shutdownTimeout = 10 * time.Second
+
+// runServe запускает HTTP сервер с погодой в JSON
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", ":8080", "адрес для прослушивания")
+	adminAddr := fs.String("admin-addr", "", "адрес служебного API (/loglevel), например 127.0.0.1:8081; по умолчанию выключено")
+	level := smartlogger.Info
+	fs.Var(&level, "log-level", "уровень логирования: trace, debug, info, warn, error")
+	debug := fs.Bool("debug", false, "выводить тайминги HTTP запросов к wttr.in в stderr")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return fmt.Errorf("использование: weather serve [--addr :8080] [--admin-addr 127.0.0.1:8081] [--log-level info]")
+	}
+
+	logger := smartlogger.NewSmartLogger(os.Stderr, "weather", smartlogger.WithColor(smartlogger.ColorAuto))
+	logger.SetLevel(level)
+	smartlogger.SetDefault(logger)
+
+	stopCycle := logger.AtomicLevel().CycleOnSignal(func(level smartlogger.Level) {
+		logger.Warnw("уровень логирования изменен по сигналу", "level", level)
+	})
+	defer stopCycle()
+
+	weather := server.New(newService(*debug), logger)
+	servers := []*http.Server{newHTTPServer(*addr, weather.Handler(), logger)}
+	if *adminAddr != "" {
+		servers = append(servers, newHTTPServer(*adminAddr, weather.AdminHandler(), logger))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func() {
+			logger.Infow("сервер запущен", "addr", srv.Addr)
+			errCh <- srv.ListenAndServe()
+		}()
+	}
+
+	// Ошибка любого сервера (например, занятый порт) останавливает оба
+	var serveErr error
+	select {
+	case serveErr = <-errCh:
+	case <-ctx.Done():
+	}
+
+	logger.Info("останавливаю сервер")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	for _, srv := range servers {
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+	}
+	remaining := len(servers)
+	if serveErr != nil {
+		remaining--
+	}
+	for ; remaining > 0; remaining-- {
+		if err := <-errCh; !errors.Is(err, http.ErrServerClosed) && serveErr == nil {
+			serveErr = err
+		}
+	}
+	if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+		return serveErr
+	}
+	return logger.Sync()
+}
+
+func newHTTPServer(addr string, handler http.Handler, logger *smartlogger.SmartLogger) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ErrorLog:          log.New(logger, "", 0),
+	}
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	smartlogger "example/src/seminar3/tasks/smart_logger"
+)
+
+// RequestIDHeader заголовок, из которого берется и в который пишется идентификатор запроса
+const RequestIDHeader = "X-Request-ID"
+
+// Максимальная длина идентификатора, принятого от клиента
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestIDFromContext возвращает идентификатор запроса, сохраненный RequestID
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestID присваивает каждому запросу идентификатор (из заголовка X-Request-ID
+// или случайный), возвращает его в ответе и кладет в контекст дочерний логгер
+// с полем request_id. Обработчики получают его через smartlogger.FromContext.
+// По завершении запроса пишется запись с методом, путем, статусом и длительностью.
+func RequestID(logger *smartlogger.SmartLogger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		requestLogger := logger.With("request_id", id)
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		ctx = smartlogger.NewContext(ctx, requestLogger)
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		requestLogger.Infow("запрос обработан",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"duration", time.Since(start),
+		)
+	})
+}
+
+// newRequestID возвращает случайный идентификатор из 16 шестнадцатеричных символов
+func newRequestID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// statusRecorder запоминает код ответа обработчика
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
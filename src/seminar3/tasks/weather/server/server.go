@@ -0,0 +1,75 @@
+// Package server HTTP режим сервиса погоды: GET /weather/{city} возвращает
+// текущую погоду в JSON. Каждый запрос получает идентификатор, который
+// попадает во все записи лога этого запроса. Служебный обработчик
+// AdminHandler (GET и PUT /loglevel) читает и меняет уровень логирования
+// без перезапуска; он не требует авторизации, поэтому обслуживается
+// на отдельном, обычно локальном адресе.
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	smartlogger "example/src/seminar3/tasks/smart_logger"
+	"example/src/seminar3/tasks/weather/client"
+)
+
+// Server HTTP обработчики поверх WeatherService
+type Server struct {
+	service *client.WeatherService
+	logger  *smartlogger.SmartLogger
+}
+
+func New(service *client.WeatherService, logger *smartlogger.SmartLogger) *Server {
+	return &Server{service: service, logger: logger}
+}
+
+// Handler возвращает маршрутизатор, обернутый в RequestID
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /weather/{city}", s.handleWeather)
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	return RequestID(s.logger, mux)
+}
+
+// AdminHandler возвращает служебный маршрутизатор с /loglevel.
+// Не публикуйте его вместе с Handler: менять уровень может любой клиент.
+func (s *Server) AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/loglevel", s.logger.AtomicLevel())
+	return RequestID(s.logger, mux)
+}
+
+// errorResponse тело ответа с ошибкой
+type errorResponse struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id"`
+}
+
+func (s *Server) handleWeather(w http.ResponseWriter, r *http.Request) {
+	logger := smartlogger.FromContext(r.Context())
+	city := r.PathValue("city")
+
+	logger.Debugw("запрашиваю погоду", "city", city)
+	data, err := s.service.GetWeatherContext(r.Context(), city)
+	if err != nil {
+		logger.Errorw("не удалось получить погоду", "city", city, "err", err)
+		writeJSON(w, http.StatusBadGateway, errorResponse{
+			Error:     err.Error(),
+			RequestID: RequestIDFromContext(r.Context()),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, data)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
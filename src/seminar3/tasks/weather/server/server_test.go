@@ -0,0 +1,176 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	smartlogger "example/src/seminar3/tasks/smart_logger"
+	"example/src/seminar3/tasks/weather/client"
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+type stubProvider struct{}
+
+func (stubProvider) GetWeather(city string) (*domain.WeatherData, error) {
+	if city == "Nowhere" {
+		return nil, errors.New("город не найден")
+	}
+	return &domain.WeatherData{City: city, Temperature: -5, Humidity: 80}, nil
+}
+
+// syncBuffer буфер, в который безопасно пишут обработчики из разных горутин
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) records(t *testing.T) []map[string]interface{} {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record), line)
+		records = append(records, record)
+	}
+	return records
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, *syncBuffer) {
+	t.Helper()
+	logs := &syncBuffer{}
+	logger := smartlogger.NewSmartLogger(logs, "weather", smartlogger.WithEncoder(smartlogger.JSONEncoder{}))
+	logger.SetLevel(smartlogger.Debug)
+
+	srv := httptest.NewServer(New(client.NewWeatherService(stubProvider{}), logger).Handler())
+	t.Cleanup(srv.Close)
+	return srv, logs
+}
+
+func TestWeatherEndpoint(t *testing.T) {
+	srv, logs := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/weather/Moscow")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var data domain.WeatherData
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&data))
+	assert.Equal(t, "Moscow", data.City)
+	assert.Equal(t, -5.0, data.Temperature)
+
+	id := resp.Header.Get(RequestIDHeader)
+	assert.Len(t, id, 16)
+
+	records := logs.records(t)
+	require.Len(t, records, 2)
+	for _, record := range records {
+		assert.Equal(t, id, record["request_id"], "все записи запроса содержат его идентификатор")
+	}
+	assert.Equal(t, "запрашиваю погоду", records[0]["msg"])
+	assert.Equal(t, "запрос обработан", records[1]["msg"])
+	assert.Equal(t, 200.0, records[1]["status"])
+	assert.Equal(t, "/weather/Moscow", records[1]["path"])
+}
+
+func TestRequestIDFromHeader(t *testing.T) {
+	srv, logs := newTestServer(t)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/weather/Nowhere", nil)
+	require.NoError(t, err)
+	req.Header.Set(RequestIDHeader, "trace-abc")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, "trace-abc", resp.Header.Get(RequestIDHeader))
+
+	var body errorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, errorResponse{Error: "город не найден", RequestID: "trace-abc"}, body)
+
+	records := logs.records(t)
+	require.Len(t, records, 3)
+	assert.Equal(t, "ERROR", records[1]["level"])
+	assert.Equal(t, "город не найден", records[1]["err"])
+	for _, record := range records {
+		assert.Equal(t, "trace-abc", record["request_id"])
+	}
+}
+
+func TestRequestIDsDiffer(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	ids := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		resp, err := http.Get(srv.URL + "/healthz")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		ids[resp.Header.Get(RequestIDHeader)] = true
+	}
+	assert.Len(t, ids, 3)
+}
+
+func TestLogLevelEndpoint(t *testing.T) {
+	logs := &syncBuffer{}
+	logger := smartlogger.NewSmartLogger(logs, "weather", smartlogger.WithEncoder(smartlogger.JSONEncoder{}))
+	weather := New(client.NewWeatherService(stubProvider{}), logger)
+	srv := httptest.NewServer(weather.Handler())
+	defer srv.Close()
+	admin := httptest.NewServer(weather.AdminHandler())
+	defer admin.Close()
+
+	resp, err := http.Get(srv.URL + "/loglevel")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "уровень не меняется через публичный API")
+
+	req, err := http.NewRequest(http.MethodPut, admin.URL+"/loglevel", strings.NewReader(`{"level":"warn"}`))
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// предыдущие записи (404 на /loglevel) не важны для проверки
+	logs.mu.Lock()
+	logs.buf.Reset()
+	logs.mu.Unlock()
+
+	resp, err = http.Get(srv.URL + "/weather/Moscow")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	resp, err = http.Get(admin.URL + "/loglevel")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, map[string]string{"level": "WARN"}, body)
+
+	// итоги запросов пишутся на уровне INFO и уже не проходят
+	logs.mu.Lock()
+	defer logs.mu.Unlock()
+	assert.Empty(t, logs.buf.String())
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"example/src/seminar3/tasks/weather/client"
+	"example/src/seminar3/tasks/weather/domain"
+)
+
+const (
+	clearScreen = "\033[H\033[2J"
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+)
+
+// runWatch периодически опрашивает погоду и перерисовывает экран
+func runWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	every := fs.Duration("every", 5*time.Minute, "интервал опроса")
+	debug := fs.Bool("debug", false, "выводить тайминги HTTP запросов в stderr")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("использование: weather watch <город> [--every 5m]")
+	}
+	city := positional[0]
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	service := newService(*debug)
+	err = service.Watch(ctx, city, *every, func(r client.Reading) {
+		renderReading(city, *every, r)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\nНаблюдение остановлено")
+	return nil
+}
+
+// renderReading перерисовывает экран и подсвечивает изменения с прошлого показания
+func renderReading(city string, every time.Duration, r client.Reading) {
+	fmt.Print(clearScreen)
+	fmt.Printf("👀 Наблюдение за погодой в %s (каждые %v, Ctrl-C для выхода)\n", city, every)
+	fmt.Printf("🕒 Обновлено: %s\n\n", r.Time.Format("15:04:05"))
+
+	if r.Err != nil {
+		fmt.Printf("%s❌ Ошибка: %v%s\n", colorRed, r.Err, colorReset)
+		if r.Previous == nil {
+			return
+		}
+		fmt.Println("\nПоследнее успешное показание:")
+		r = client.Reading{Data: r.Previous}
+	}
+
+	data := r.Data
+	change := r.Change()
+	fmt.Printf("🌡️  Температура: %.1f°C %s\n", data.Temperature, formatDelta(change.TemperatureDelta, "°C"))
+	fmt.Printf("🤔 Ощущается как: %.1f°C %s\n", data.FeelsLike, formatDelta(change.FeelsLikeDelta, "°C"))
+	fmt.Printf("💧 Влажность: %d%% %s\n", data.Humidity, formatDelta(float64(change.HumidityDelta), "%"))
+	fmt.Printf("💨 Скорость ветра: %.1f км/ч %s\n", data.WindSpeed, formatDelta(change.WindSpeedDelta, " км/ч"))
+	fmt.Printf("📝 Описание: %s\n", formatDescription(data, change))
+}
+
+func formatDelta(delta float64, unit string) string {
+	switch {
+	case delta > 0:
+		return fmt.Sprintf("%s(+%.1f%s)%s", colorRed, delta, unit, colorReset)
+	case delta < 0:
+		return fmt.Sprintf("%s(%.1f%s)%s", colorGreen, delta, unit, colorReset)
+	default:
+		return ""
+	}
+}
+
+func formatDescription(data *domain.WeatherData, change domain.Change) string {
+	if !change.DescriptionChanged {
+		return data.Description
+	}
+	return fmt.Sprintf("%s%s%s (было: %s)", colorYellow, data.Description, colorReset, change.PreviousDescription)
+}